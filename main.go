@@ -3,19 +3,52 @@ package main
 import (
 	"flag"
 	"fmt"
+	"mem-tests/pkg/escape"
+	"mem-tests/pkg/layout"
 	"mem-tests/pkg/memory"
+	"mem-tests/pkg/populate"
+	"mem-tests/pkg/term"
 	"mem-tests/pkg/visualizer"
+	"mem-tests/tests/bitpack"
+	"mem-tests/tests/boxing"
+	"mem-tests/tests/interning"
+	maps "mem-tests/tests/maps"
+	"mem-tests/tests/narrowing"
+	"mem-tests/tests/pool"
+	"mem-tests/tests/slab"
+	slices "mem-tests/tests/slices"
+	"mem-tests/tests/soa"
 	structs "mem-tests/tests/struct"
+	"mem-tests/tests/timestamps"
 	"os"
+	"reflect"
+	"sort"
+	"strconv"
 	"strings"
 )
 
+// style colors the results printed to the terminal
+var style term.Style
+
 func main() {
-	// Define available tests
+	// Define available tests. Narrowing observes the populations of the
+	// small and large struct tests, so it shares their profiles.
+	structSmall := &structs.StructOrderTest{}
+	structBig := &structs.StructBigTest{}
 	tests := map[string]memory.MemoryTest{
-		"struct-small": &structs.StructOrderTest{},
-		"struct-big":   &structs.StructBigTest{},
+		"struct-small": structSmall,
+		"struct-big":   structBig,
 		"struct-multi": &structs.MultiTypeStructTest{},
+		"maps":         &maps.MapOverheadTest{},
+		"slices":       &slices.SliceGrowthTest{},
+		"interning":    &interning.StringInterningTest{},
+		"pool":         &pool.PoolChurnTest{},
+		"slab":         &slab.SlabAllocatorTest{},
+		"boxing":       &boxing.InterfaceBoxingTest{},
+		"soa":          &soa.ArrayOfStructsTest{},
+		"bitpack":      &bitpack.BitPackingTest{},
+		"narrowing":    narrowing.NewIntegerNarrowingTest(structSmall, structBig),
+		"timestamps":   &timestamps.TimestampTest{},
 		// Add more tests here as you create them
 	}
 
@@ -24,71 +57,304 @@ func main() {
 	var testName string
 	var visualize bool
 	var outputFormat string
+	var seed int64
+	var profileSpec string
+	var escapeAnalysis bool
+	var templateDir string
+	var outputFile string
+	var pagesDir string
+	var exportPages bool
+	var retentionSpec string
+	var noColor bool
+	var tui bool
+	var resultsFile string
+	var narrowHeadroom float64
 
 	flag.BoolVar(&listTests, "list", false, "List available tests")
 	flag.StringVar(&testName, "test", "", "Name of test to run (comma separated for multiple)")
 	flag.BoolVar(&visualize, "viz", false, "Visualize test results")
-	flag.StringVar(&outputFormat, "format", "stdout", "Output format: stdout, html, png")
+	flag.StringVar(&outputFormat, "format", "stdout", "Output format: stdout, html, tui")
+	flag.Int64Var(&seed, "seed", 0, "Seed for test data generation (random if not set)")
+	flag.StringVar(&profileSpec, "profile", "", "Population profile for reference fields, e.g. realistic or struct-small=heavy,struct-big=small")
+	flag.BoolVar(&escapeAnalysis, "escape", false, "Attach the compiler's escape analysis (go build -gcflags=-m=2) to each test result")
+	flag.StringVar(&templateDir, "template-dir", "", "Directory of HTML templates overriding the embedded report templates by name")
+	flag.StringVar(&outputFile, "output", visualizer.DefaultOutputFile, "Path of the HTML report")
+	flag.StringVar(&pagesDir, "pages-dir", visualizer.DefaultPagesDir, "Directory the HTML results are exported to for GitHub Pages")
+	flag.BoolVar(&exportPages, "pages", true, "Export HTML results to the GitHub Pages directory")
+	flag.StringVar(&retentionSpec, "retention", "", "History runs to keep after each export, e.g. last=10 or daily=7,weekly=4,monthly=12 (keep all if not set)")
+	flag.BoolVar(&noColor, "no-color", false, "Disable colored output (also disabled when NO_COLOR is set or output is not a terminal)")
+	flag.BoolVar(&tui, "tui", false, "Browse the results in a full-screen terminal UI")
+	flag.StringVar(&resultsFile, "results", "", "Load results saved by an HTML export (docs/history/<date>/results-<time>.json) instead of running tests")
+	flag.Float64Var(&narrowHeadroom, "narrow-headroom", layout.DefaultHeadroom, "Growth over the observed values a narrowed integer type must leave room for, e.g. 1 for twice the largest value seen")
 	flag.Parse()
 
+	style = term.NewStyle(os.Stdout, noColor)
+
+	// -viz -format=tui is another way to ask for the TUI, which opens once
+	if visualize && strings.EqualFold(outputFormat, "tui") {
+		tui, visualize = true, false
+	}
+
+	// Only override the time-based default seed when one was given explicitly
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == "seed" {
+			memory.SetSeed(seed)
+		}
+	})
+
 	// List available tests if requested
 	if listTests {
 		fmt.Println("Available tests:")
-		for name, test := range tests {
-			fmt.Printf("  %s - %s\n", name, test.Name())
+		for _, name := range sortedTestNames(tests) {
+			fmt.Printf("  %s - %s\n", name, tests[name].Name())
 		}
+		fmt.Printf("\nAvailable population profiles: %s\n", strings.Join(populate.Names(), ", "))
 		return
 	}
 
+	// Apply population profiles to the tests that support them
+	if err := applyProfiles(tests, profileSpec); err != nil {
+		fmt.Printf("Invalid -profile: %v\n", err)
+		os.Exit(1)
+	}
+
+	if narrowHeadroom < 0 {
+		fmt.Printf("Invalid -narrow-headroom: %v is negative\n", narrowHeadroom)
+		os.Exit(1)
+	}
+	tests["narrowing"].(*narrowing.IntegerNarrowingTest).SetHeadroom(narrowHeadroom)
+
+	retention, err := visualizer.ParseRetention(retentionSpec)
+	if err != nil {
+		fmt.Printf("Invalid -retention: %v\n", err)
+		os.Exit(1)
+	}
+
+	// Run the escape analysis once for all test packages
+	var escapeReport *escape.Report
+	if escapeAnalysis && resultsFile == "" {
+		report, err := analyzeEscapes(tests)
+		if err != nil {
+			fmt.Printf("Escape analysis failed: %v\n", err)
+		} else {
+			escapeReport = report
+		}
+	}
+
 	var results []memory.TestResult
 
-	// Run all tests if none specified
-	if testName == "" {
-		fmt.Println("Running all tests...")
-		results = runAllTests(tests)
-	} else {
-		// Run specified tests
-		testNames := strings.Split(testName, ",")
-		for _, name := range testNames {
-			name = strings.TrimSpace(name)
-			test, exists := tests[name]
-			if !exists {
-				fmt.Printf("Test '%s' not found. Use -list to see available tests.\n", name)
-				os.Exit(1)
+	if resultsFile != "" {
+		// Show results saved by an earlier export instead of running the tests
+		loaded, err := visualizer.LoadResults(resultsFile)
+		if err != nil {
+			fmt.Printf("Failed to load results: %v\n", err)
+			os.Exit(1)
+		}
+		results = loaded
+
+		if !tui {
+			for _, result := range results {
+				fmt.Println("\n=== Results ===")
+				printTestResult(result)
 			}
+		}
+	} else {
+		fmt.Printf("Using seed: %d\n", memory.Seed())
+
+		// Run all tests if none specified
+		if testName == "" {
+			fmt.Println("Running all tests...")
+			results = runAllTests(tests, escapeReport)
+		} else {
+			// Run specified tests
+			testNames := strings.Split(testName, ",")
+			for _, name := range testNames {
+				name = strings.TrimSpace(name)
+				test, exists := tests[name]
+				if !exists {
+					fmt.Printf("Test '%s' not found. Use -list to see available tests.\n", name)
+					os.Exit(1)
+				}
 
-			fmt.Printf("\n=== Running test: %s ===\n", test.Name())
-			initialMem := memory.PrepareMemoryTest()
-			fmt.Printf("Initial memory usage: %d bytes\n", initialMem)
+				fmt.Printf("\n=== Running test: %s ===\n", test.Name())
+				initialMem := memory.PrepareMemoryTest()
+				fmt.Printf("Initial memory usage: %d bytes\n", initialMem)
 
-			result := test.Run()
-			results = append(results, result)
+				result := test.Run()
+				result.Seed = memory.Seed()
+				attachEscapeAnalysis(&result, test, escapeReport)
+				results = append(results, result)
 
-			fmt.Println("\n=== Results ===")
-			printTestResult(result)
+				fmt.Println("\n=== Results ===")
+				printTestResult(result)
 
-			memory.CleanupAfterTest()
+				memory.CleanupAfterTest()
+			}
 		}
 	}
 
 	// Visualize results if requested
 	if visualize && len(results) > 0 {
 		v := visualizer.New(outputFormat)
+		if h, ok := v.(*visualizer.HTMLVisualizer); ok {
+			h.TemplateDir = templateDir
+			h.OutputFile = outputFile
+			h.PagesDir = pagesDir
+			// Loaded results are already in the Pages history, so exporting
+			// them again would add the old run as a new trend point
+			h.ExportToGitHubPages = exportPages && resultsFile == ""
+			h.Retention = retention
+		}
+		if t, ok := v.(*visualizer.TerminalVisualizer); ok {
+			t.Style = style
+		}
 		if err := v.Visualize(results); err != nil {
 			fmt.Printf("Error visualizing results: %v\n", err)
 		}
 	}
+
+	// Browse the results in the terminal UI if requested
+	if tui && len(results) > 0 {
+		if err := (&visualizer.TUIVisualizer{Style: style}).Visualize(results); err != nil {
+			fmt.Printf("Error opening the TUI: %v\n", err)
+		}
+	}
 }
 
-func runAllTests(tests map[string]memory.MemoryTest) []memory.TestResult {
-	var results []memory.TestResult
+// sortedTestNames returns the keys of the tests in order, so tests run and
+// are listed in the same order every time
+func sortedTestNames(tests map[string]memory.MemoryTest) []string {
+	names := make([]string, 0, len(tests))
+	for name := range tests {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// applyProfiles parses a profile spec and sets the profile on each matching test.
+// A bare profile name applies to every test, while test=profile pairs select a
+// profile for a single test and take precedence.
+func applyProfiles(tests map[string]memory.MemoryTest, spec string) error {
+	if spec == "" {
+		return nil
+	}
+
+	var defaultProfile *populate.Profile
+	perTest := make(map[string]populate.Profile)
+
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
 
+		testName, profileName, hasTest := strings.Cut(part, "=")
+		if !hasTest {
+			profileName = testName
+		}
+
+		profile, err := populate.Lookup(strings.TrimSpace(profileName))
+		if err != nil {
+			return err
+		}
+
+		if !hasTest {
+			defaultProfile = &profile
+			continue
+		}
+
+		testName = strings.TrimSpace(testName)
+		test, exists := tests[testName]
+		if !exists {
+			return fmt.Errorf("test '%s' not found", testName)
+		}
+		if _, ok := test.(populate.Profiled); !ok {
+			return fmt.Errorf("test '%s' does not support population profiles", testName)
+		}
+		perTest[testName] = profile
+	}
+
+	for name, test := range tests {
+		profiled, ok := test.(populate.Profiled)
+		if !ok {
+			continue
+		}
+		if profile, ok := perTest[name]; ok {
+			profiled.SetProfile(profile)
+		} else if defaultProfile != nil {
+			profiled.SetProfile(*defaultProfile)
+		}
+	}
+
+	return nil
+}
+
+// testPackage returns the import path of the package a test is defined in
+func testPackage(test memory.MemoryTest) string {
+	t := reflect.TypeOf(test)
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t.PkgPath()
+}
+
+// analyzeEscapes runs the escape analysis on every package that defines a test
+func analyzeEscapes(tests map[string]memory.MemoryTest) (*escape.Report, error) {
+	seen := make(map[string]bool)
+	var pkgPaths []string
 	for _, test := range tests {
+		if pkg := testPackage(test); !seen[pkg] {
+			seen[pkg] = true
+			pkgPaths = append(pkgPaths, pkg)
+		}
+	}
+	sort.Strings(pkgPaths)
+
+	fmt.Printf("Running escape analysis on %s\n", strings.Join(pkgPaths, ", "))
+	return escape.Analyze(pkgPaths...)
+}
+
+// testEntry returns the name the compiler gives a test's Run method, such as
+// (*StructOrderTest).Run, which is where the functions the test runs start
+func testEntry(test memory.MemoryTest) string {
+	t := reflect.TypeOf(test)
+	if t.Kind() != reflect.Pointer {
+		return t.Name() + ".Run"
+	}
+	if _, ok := t.Elem().MethodByName("Run"); ok {
+		return t.Elem().Name() + ".Run"
+	}
+	return fmt.Sprintf("(*%s).Run", t.Elem().Name())
+}
+
+// attachEscapeAnalysis stores the escape decisions made in the functions the
+// test runs, grouped by function, along with the variables that were moved to
+// the heap. Decisions in functions of the same package that only other tests
+// run are left out.
+func attachEscapeAnalysis(result *memory.TestResult, test memory.MemoryTest, report *escape.Report) {
+	if report == nil {
+		return
+	}
+
+	pkg := testPackage(test)
+	report = report.Reachable(pkg, testEntry(test))
+	result.OtherStats["EscapeAnalysis"] = report.ByFunction(pkg)
+	result.OtherStats["MovedToHeap"] = report.MovedToHeap(pkg)
+}
+
+func runAllTests(tests map[string]memory.MemoryTest, escapeReport *escape.Report) []memory.TestResult {
+	var results []memory.TestResult
+
+	for _, name := range sortedTestNames(tests) {
+		test := tests[name]
 		fmt.Printf("\n\n=== Running test: %s ===\n", test.Name())
 		initialMem := memory.PrepareMemoryTest()
 		fmt.Printf("Initial memory usage: %d bytes\n", initialMem)
 
 		result := test.Run()
+		result.Seed = memory.Seed()
+		attachEscapeAnalysis(&result, test, escapeReport)
 		results = append(results, result)
 
 		fmt.Println("\n=== Results ===")
@@ -101,55 +367,142 @@ func runAllTests(tests map[string]memory.MemoryTest) []memory.TestResult {
 }
 
 func printTestResult(result memory.TestResult) {
-	fmt.Printf("Test: %s\n", result.Name)
-	fmt.Printf("Memory difference: %d bytes\n", result.MemoryUsed)
+	fmt.Printf("Test: %s\n", style.Bold(result.Name))
+	fmt.Printf("Memory difference: %s\n", formatBytes(result.MemoryUsed))
+	fmt.Printf("Seed: %d\n", result.Seed)
 
 	if result.PerObjectSize > 0 {
 		fmt.Printf("Memory per object: %.2f bytes\n", result.PerObjectSize)
 	}
 
+	printEscapeAnalysis(result)
+
 	// Check for multi-type test results
 	if typeResults, ok := result.OtherStats["TypeResults"].(map[string]map[string]interface{}); ok {
 		fmt.Println("\nDetailed results by type:")
 
-		for typeName, typeData := range typeResults {
-			fmt.Printf("\n  %s:\n", typeName)
+		typeNames := make([]string, 0, len(typeResults))
+		for typeName := range typeResults {
+			typeNames = append(typeNames, typeName)
+		}
+		sort.Strings(typeNames)
 
-			if count, ok := typeData["ObjectCount"].(int); ok {
-				fmt.Printf("    Object count: %d\n", count)
-			}
+		variants := result.Variants()
+		table := term.NewTable("Type", "Objects", variants.Optimized, variants.Unoptimized, "Saved", "Saving").AlignRight(1, 2, 3, 4, 5)
+		for _, typeName := range typeNames {
+			typeData := typeResults[typeName]
+			count, _ := typeData["ObjectCount"].(int)
+			optMem, _ := typeData["OptimizedMemory"].(uint64)
+			unoptMem, _ := typeData["UnoptimizedMemory"].(uint64)
+			saved, _ := typeData["MemorySaved"].(uint64)
+			pct, _ := typeData["SavingPercent"].(float64)
+			table.Row(typeName, strconv.Itoa(count),
+				memory.FormatBytes(optMem), memory.FormatBytes(unoptMem),
+				style.Signed(pct, memory.FormatBytes(saved)), style.Signed(pct, fmt.Sprintf("%.2f%%", pct)))
+		}
+		table.Write(os.Stdout, "  ", style)
 
-			if optMem, ok := typeData["OptimizedMemory"].(uint64); ok {
-				fmt.Printf("    Optimized memory: %d bytes\n", optMem)
-			}
+		if totalSaving, ok := result.OtherStats["TotalSaving"].(uint64); ok {
+			fmt.Printf("\nTotal memory saving: %s\n", style.Saving(formatBytes(totalSaving)))
+		}
 
-			if unoptMem, ok := typeData["UnoptimizedMemory"].(uint64); ok {
-				fmt.Printf("    Unoptimized memory: %d bytes\n", unoptMem)
-			}
+		return
+	}
 
-			if saved, ok := typeData["MemorySaved"].(uint64); ok {
-				fmt.Printf("    Memory saved: %d bytes\n", saved)
-			}
+	// Print standard stats, sorted so the output is the same every run
+	keys := make([]string, 0, len(result.OtherStats))
+	keyWidth := 0
+	for key, value := range result.OtherStats {
+		switch value.(type) {
+		case map[string][]escape.Decision, []escape.Decision:
+			// Printed by printEscapeAnalysis
+		case layout.Layout:
+			// Drawn by the visualizer
+		default:
+			keys = append(keys, key)
+			keyWidth = max(keyWidth, len(key))
+		}
+	}
+	sort.Strings(keys)
 
-			if pct, ok := typeData["SavingPercent"].(float64); ok {
-				fmt.Printf("    Saving: %.2f%%\n", pct)
-			}
+	for _, key := range keys {
+		fmt.Printf("%-*s  %s\n", keyWidth+1, key+":", formatStat(key, result.OtherStats[key]))
+	}
+}
+
+// byteStatSuffixes mark the stats whose integer values are byte counts
+var byteStatSuffixes = []string{"Memory", "Size", "Padding", "Saving", "Saved"}
+
+// formatStat formats a result stat for display. Byte counts get human-readable
+// units and percentages are colored as savings or regressions.
+func formatStat(key string, value any) string {
+	switch v := value.(type) {
+	case float64:
+		if strings.HasSuffix(key, "Percent") {
+			return style.Signed(v, fmt.Sprintf("%.2f%%", v))
 		}
+		return fmt.Sprintf("%.2f", v)
+	}
 
-		if totalSaving, ok := result.OtherStats["TotalSaving"].(uint64); ok {
-			fmt.Printf("\nTotal memory saving: %d bytes\n", totalSaving)
+	for _, suffix := range byteStatSuffixes {
+		if !strings.HasSuffix(key, suffix) {
+			continue
 		}
+		switch v := value.(type) {
+		case uint64:
+			return formatBytes(v)
+		case uintptr:
+			return formatBytes(uint64(v))
+		case int:
+			if v >= 0 {
+				return formatBytes(uint64(v))
+			}
+		}
+	}
+	return fmt.Sprintf("%v", value)
+}
+
+// formatBytes formats a byte count in human-readable units followed by the
+// exact count
+func formatBytes(n uint64) string {
+	if n < 1024 {
+		return fmt.Sprintf("%d bytes", n)
+	}
+	return fmt.Sprintf("%s %s", memory.FormatBytes(n), style.Dim(fmt.Sprintf("(%d bytes)", n)))
+}
 
+// printEscapeAnalysis summarizes the escape decisions attached to a result and
+// flags every variable that was moved to the heap
+func printEscapeAnalysis(result memory.TestResult) {
+	functions, ok := result.OtherStats["EscapeAnalysis"].(map[string][]escape.Decision)
+	if !ok {
 		return
 	}
 
-	// Print standard stats
-	for key, value := range result.OtherStats {
-		switch v := value.(type) {
-		case float64:
-			fmt.Printf("%s: %.2f\n", key, v)
-		default:
-			fmt.Printf("%s: %v\n", key, v)
+	names := make([]string, 0, len(functions))
+	for name := range functions {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	fmt.Println("\nEscape analysis:")
+	for _, name := range names {
+		var escapes, stack int
+		for _, d := range functions[name] {
+			switch d.Kind {
+			case escape.Escapes, escape.MovedToHeap:
+				escapes++
+			case escape.NoEscape:
+				stack++
+			}
+		}
+		fmt.Printf("  %s: %d escape to heap, %d stay on stack\n", name, escapes, stack)
+	}
+
+	if moved, ok := result.OtherStats["MovedToHeap"].([]escape.Decision); ok && len(moved) > 0 {
+		fmt.Printf("\n  %s\n", style.Regression(fmt.Sprintf("WARNING: %d variables moved to the heap:", len(moved))))
+		for _, d := range moved {
+			fmt.Printf("    %s in %s (%s)\n", d.Subject, d.Function, d.Position())
 		}
 	}
 }
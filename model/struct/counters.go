@@ -0,0 +1,78 @@
+package model
+
+import "sync/atomic"
+
+// Cache stats struct definitions
+
+// CacheStatsOptimizedStruct represents the counters of one cache shard,
+// which many goroutines update atomically. The counters are atomic.Uint64,
+// which the compiler aligns to 8 bytes on every architecture, so on 32-bit
+// platforms the struct is padded to a multiple of 8 bytes and the counters
+// stay aligned in every element of a slice. As plain uint64 fields the
+// struct would be 44 bytes there, misaligning every other element.
+type CacheStatsOptimizedStruct struct {
+	// 8-byte atomic counters
+	Hits   atomic.Uint64
+	Misses atomic.Uint64
+	// Slice field
+	Keys []string
+	// String field
+	Name string
+	// 4-byte field
+	Shard int32
+	// 1-byte field
+	Evicting bool
+}
+
+// RecordHit counts a cache hit
+func (s *CacheStatsOptimizedStruct) RecordHit() {
+	s.Hits.Add(1)
+}
+
+// RecordMiss counts a cache miss
+func (s *CacheStatsOptimizedStruct) RecordMiss() {
+	s.Misses.Add(1)
+}
+
+// HitRate returns the share of lookups that were hits
+func (s *CacheStatsOptimizedStruct) HitRate() float64 {
+	hits, misses := s.Hits.Load(), s.Misses.Load()
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
+
+// CacheStatsUnoptimizedStruct represents an unoptimized cache shard counters struct
+type CacheStatsUnoptimizedStruct struct {
+	// 8-byte atomic counters
+	Hits   atomic.Uint64
+	Misses atomic.Uint64
+	// 1-byte field (causing padding)
+	Evicting bool
+	// Slice field
+	Keys []string
+	// 4-byte field (causing padding)
+	Shard int32
+	// String field
+	Name string
+}
+
+// RecordHit counts a cache hit
+func (s *CacheStatsUnoptimizedStruct) RecordHit() {
+	s.Hits.Add(1)
+}
+
+// RecordMiss counts a cache miss
+func (s *CacheStatsUnoptimizedStruct) RecordMiss() {
+	s.Misses.Add(1)
+}
+
+// HitRate returns the share of lookups that were hits
+func (s *CacheStatsUnoptimizedStruct) HitRate() float64 {
+	hits, misses := s.Hits.Load(), s.Misses.Load()
+	if hits+misses == 0 {
+		return 0
+	}
+	return float64(hits) / float64(hits+misses)
+}
@@ -0,0 +1,97 @@
+package model
+
+import "unique"
+
+// Interned struct definitions
+//
+// These mirror the config and GraphQL structs, but store their low-cardinality
+// string fields as unique.Handle values. A handle is a single pointer (8 bytes)
+// to a canonical copy of the string, while a string header is 16 bytes.
+
+// ConfigHandleOptimizedStruct represents an optimized config struct with interned strings
+type ConfigHandleOptimizedStruct struct {
+	// Handles (pointers, 8 bytes)
+	Name        unique.Handle[string]
+	Description unique.Handle[string]
+	Environment unique.Handle[string]
+	// 8-byte fields
+	UpdatedAt int64
+	CreatedAt int64
+	// 4-byte fields
+	MaxConnections int32
+	Timeout        int32
+	// 2-byte fields
+	Port uint16
+	// 1-byte fields
+	Debug   bool
+	Enabled bool
+}
+
+// ConfigHandleUnoptimizedStruct represents an unoptimized config struct with interned strings
+type ConfigHandleUnoptimizedStruct struct {
+	// 1-byte fields first (causing padding)
+	Debug   bool
+	Enabled bool
+	// 2-byte field
+	Port uint16
+	// Handle (pointer)
+	Name unique.Handle[string]
+	// 4-byte field
+	Timeout int32
+	// Handle
+	Environment unique.Handle[string]
+	// 4-byte field
+	MaxConnections int32
+	// 8-byte field
+	CreatedAt int64
+	// Handle
+	Description unique.Handle[string]
+	// 8-byte field
+	UpdatedAt int64
+}
+
+// GraphQLHandleOptimizedStruct represents an optimized GraphQL query struct with interned strings
+type GraphQLHandleOptimizedStruct struct {
+	// String (unique per query, so not interned)
+	QueryID string
+	// Handles (pointers, 8 bytes)
+	Operation unique.Handle[string]
+	ClientID  unique.Handle[string]
+	// 8-byte fields
+	Timestamp int64
+	Duration  int64
+	// 4-byte fields
+	Depth           int32
+	ComplexityScore float32
+	// 2-byte fields
+	FragmentCount uint16
+	// 1-byte fields
+	IsMutation   bool
+	HasVariables bool
+	Cached       bool
+}
+
+// GraphQLHandleUnoptimizedStruct represents an unoptimized GraphQL query struct with interned strings
+type GraphQLHandleUnoptimizedStruct struct {
+	// 1-byte fields first (causing padding)
+	IsMutation bool
+	Cached     bool
+	// 4-byte field
+	Depth int32
+	// Handle
+	Operation unique.Handle[string]
+	// 1-byte field
+	HasVariables bool
+	// 2-byte field
+	FragmentCount uint16
+	// 8-byte field
+	Timestamp int64
+	// String field
+	QueryID string
+	// 4-byte field
+	ComplexityScore float32
+	// Handle
+	ClientID unique.Handle[string]
+	// 8-byte field
+	Duration int64
+}
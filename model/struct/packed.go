@@ -0,0 +1,394 @@
+package model
+
+import (
+	"net"
+	"time"
+)
+
+// Bit-packed struct definitions
+//
+// These mirror the optimized structs, but store their bool fields as bits of
+// a single Flags field. Each flag is read and written through accessor methods.
+
+// Flags8 is a set of up to 8 boolean flags packed into one byte
+type Flags8 uint8
+
+// Has reports whether flag is set
+func (f Flags8) Has(flag Flags8) bool {
+	return f&flag != 0
+}
+
+// Set sets or clears flag
+func (f *Flags8) Set(flag Flags8, on bool) {
+	if on {
+		*f |= flag
+	} else {
+		*f &^= flag
+	}
+}
+
+// Flags16 is a set of up to 16 boolean flags packed into two bytes
+type Flags16 uint16
+
+// Has reports whether flag is set
+func (f Flags16) Has(flag Flags16) bool {
+	return f&flag != 0
+}
+
+// Set sets or clears flag
+func (f *Flags16) Set(flag Flags16, on bool) {
+	if on {
+		*f |= flag
+	} else {
+		*f &^= flag
+	}
+}
+
+// SmallPackedStruct represents a bit-packed small struct
+type SmallPackedStruct struct {
+	// String fields (pointers - 8 bytes or 16 bytes on 64-bit systems)
+	StringField  string
+	StringFieldB string
+
+	// Slices (pointers + length + capacity - 24 bytes on 64-bit systems)
+	SliceField []int
+	MapField   map[string]int
+
+	// Complex data types
+	DurationField time.Duration // 8 bytes (int64)
+	TimeField     time.Time     // 24 bytes typically
+
+	// 8-byte aligned fields
+	Float64Field float64 // 8 bytes
+	Int64Field   int64   // 8 bytes
+	Uint64Field  uint64  // 8 bytes
+	Int64FieldB  int64   // 8 bytes
+
+	// Interface (16 bytes typically)
+	InterfaceField interface{}
+
+	// 4-byte aligned fields
+	Float32Field float32 // 4 bytes
+	Int32Field   int32   // 4 bytes
+	Uint32Field  uint32  // 4 bytes
+	Int32FieldB  int32   // 4 bytes
+
+	// 2-byte aligned fields
+	Int16Field  int16  // 2 bytes
+	Uint16Field uint16 // 2 bytes
+	Int16FieldB int16  // 2 bytes
+
+	// 1-byte fields at the end
+	Int8Field  int8   // 1 byte
+	Uint8Field uint8  // 1 byte
+	ByteField  byte   // 1 byte
+	Flags      Flags8 // packed bool fields
+	RuneField  rune   // technically 4 bytes (alias for int32)
+}
+
+// Flags of SmallPackedStruct
+const (
+	SmallBoolField Flags8 = 1 << iota
+	SmallBoolFieldB
+)
+
+// BoolField reports whether the BoolField flag is set
+func (s *SmallPackedStruct) BoolField() bool { return s.Flags.Has(SmallBoolField) }
+
+// SetBoolField sets or clears the BoolField flag
+func (s *SmallPackedStruct) SetBoolField(on bool) { s.Flags.Set(SmallBoolField, on) }
+
+// BoolFieldB reports whether the BoolFieldB flag is set
+func (s *SmallPackedStruct) BoolFieldB() bool { return s.Flags.Has(SmallBoolFieldB) }
+
+// SetBoolFieldB sets or clears the BoolFieldB flag
+func (s *SmallPackedStruct) SetBoolFieldB(on bool) { s.Flags.Set(SmallBoolFieldB, on) }
+
+// LargePackedStruct represents a bit-packed large struct
+type LargePackedStruct struct {
+	// Complex fields with pointers and internal structures
+	UserProfile     map[string]string  // Map is a pointer
+	ActivityHistory []time.Time        // Slice is a pointer + len + cap
+	Settings        map[string]bool    // Another map
+	Tags            []string           // Slice of strings
+	IPAddress       net.IP             // IP address (slice under the hood)
+	Listener        net.Listener       // Interface (pointer + type data)
+	Conn            net.Conn           // Another interface
+	Metrics         map[string]float64 // Map of metrics
+
+	// 8-byte aligned fields
+	CreatedAt         time.Time // 24 bytes typically
+	UpdatedAt         time.Time
+	ExpiresAt         time.Time
+	DueDate           time.Time
+	Duration          time.Duration // 8 bytes (int64)
+	Timeout           time.Duration
+	TransactionID     uint64
+	UserID            uint64
+	AccountID         uint64
+	OrderID           uint64
+	ParentID          uint64
+	RequestTimestamp  int64
+	ResponseTimestamp int64
+	Balance           float64
+	Credit            float64
+	Score             float64
+
+	// 4-byte fields
+	StatusCode    int32
+	ResponseCode  int32
+	RequestCount  int32
+	RetryAttempts int32
+	ErrorCount    int32
+	BatchSize     int32
+	ServiceTime   float32
+	CPUTime       float32
+	MemoryUsage   float32
+	DiskUsage     float32
+	NetworkUsage  float32
+	Percentage    float32
+
+	// 2-byte fields
+	ErrorCode       uint16
+	ProtocolVersion uint16
+	ServerRegion    uint16
+	ClientRegion    uint16
+	Port            uint16
+	BackupPort      uint16
+
+	// Packed bool fields
+	Flags Flags16
+
+	// 1-byte fields
+	Priority         uint8
+	CompressionLevel uint8
+	Importance       uint8
+	Status           byte
+	Type             byte
+}
+
+// Flags of LargePackedStruct
+const (
+	LargeIsSuccess Flags16 = 1 << iota
+	LargeIsRetry
+	LargeIsCached
+	LargeIsVerified
+	LargeIsActive
+	LargeIsAdmin
+	LargeIsTest
+	LargeIsPriority
+	LargeIsFlagged
+)
+
+// IsSuccess reports whether the IsSuccess flag is set
+func (s *LargePackedStruct) IsSuccess() bool { return s.Flags.Has(LargeIsSuccess) }
+
+// SetIsSuccess sets or clears the IsSuccess flag
+func (s *LargePackedStruct) SetIsSuccess(on bool) { s.Flags.Set(LargeIsSuccess, on) }
+
+// IsRetry reports whether the IsRetry flag is set
+func (s *LargePackedStruct) IsRetry() bool { return s.Flags.Has(LargeIsRetry) }
+
+// SetIsRetry sets or clears the IsRetry flag
+func (s *LargePackedStruct) SetIsRetry(on bool) { s.Flags.Set(LargeIsRetry, on) }
+
+// IsCached reports whether the IsCached flag is set
+func (s *LargePackedStruct) IsCached() bool { return s.Flags.Has(LargeIsCached) }
+
+// SetIsCached sets or clears the IsCached flag
+func (s *LargePackedStruct) SetIsCached(on bool) { s.Flags.Set(LargeIsCached, on) }
+
+// IsVerified reports whether the IsVerified flag is set
+func (s *LargePackedStruct) IsVerified() bool { return s.Flags.Has(LargeIsVerified) }
+
+// SetIsVerified sets or clears the IsVerified flag
+func (s *LargePackedStruct) SetIsVerified(on bool) { s.Flags.Set(LargeIsVerified, on) }
+
+// IsActive reports whether the IsActive flag is set
+func (s *LargePackedStruct) IsActive() bool { return s.Flags.Has(LargeIsActive) }
+
+// SetIsActive sets or clears the IsActive flag
+func (s *LargePackedStruct) SetIsActive(on bool) { s.Flags.Set(LargeIsActive, on) }
+
+// IsAdmin reports whether the IsAdmin flag is set
+func (s *LargePackedStruct) IsAdmin() bool { return s.Flags.Has(LargeIsAdmin) }
+
+// SetIsAdmin sets or clears the IsAdmin flag
+func (s *LargePackedStruct) SetIsAdmin(on bool) { s.Flags.Set(LargeIsAdmin, on) }
+
+// IsTest reports whether the IsTest flag is set
+func (s *LargePackedStruct) IsTest() bool { return s.Flags.Has(LargeIsTest) }
+
+// SetIsTest sets or clears the IsTest flag
+func (s *LargePackedStruct) SetIsTest(on bool) { s.Flags.Set(LargeIsTest, on) }
+
+// IsPriority reports whether the IsPriority flag is set
+func (s *LargePackedStruct) IsPriority() bool { return s.Flags.Has(LargeIsPriority) }
+
+// SetIsPriority sets or clears the IsPriority flag
+func (s *LargePackedStruct) SetIsPriority(on bool) { s.Flags.Set(LargeIsPriority, on) }
+
+// IsFlagged reports whether the IsFlagged flag is set
+func (s *LargePackedStruct) IsFlagged() bool { return s.Flags.Has(LargeIsFlagged) }
+
+// SetIsFlagged sets or clears the IsFlagged flag
+func (s *LargePackedStruct) SetIsFlagged(on bool) { s.Flags.Set(LargeIsFlagged, on) }
+
+// APIPackedStruct represents a bit-packed API request struct
+type APIPackedStruct struct {
+	// 8-byte fields first
+	RequestID uint64
+	UserID    uint64
+	Timestamp int64
+	SessionID uint64
+	// 4-byte fields
+	StatusCode int32
+	Latency    float32
+	// 2-byte fields
+	APIVersion uint16
+	// 1-byte fields
+	Method byte
+	// Packed bool fields
+	Flags Flags8
+}
+
+// Flags of APIPackedStruct
+const (
+	APIAuthenticated Flags8 = 1 << iota
+	APICached
+)
+
+// Authenticated reports whether the Authenticated flag is set
+func (s *APIPackedStruct) Authenticated() bool { return s.Flags.Has(APIAuthenticated) }
+
+// SetAuthenticated sets or clears the Authenticated flag
+func (s *APIPackedStruct) SetAuthenticated(on bool) { s.Flags.Set(APIAuthenticated, on) }
+
+// Cached reports whether the Cached flag is set
+func (s *APIPackedStruct) Cached() bool { return s.Flags.Has(APICached) }
+
+// SetCached sets or clears the Cached flag
+func (s *APIPackedStruct) SetCached(on bool) { s.Flags.Set(APICached, on) }
+
+// ConfigPackedStruct represents a bit-packed config struct
+type ConfigPackedStruct struct {
+	// String fields (contain pointers, which are 8 bytes)
+	Name        string
+	Description string
+	Environment string
+	// 8-byte fields
+	UpdatedAt int64
+	CreatedAt int64
+	// 4-byte fields
+	MaxConnections int32
+	Timeout        int32
+	// 2-byte fields
+	Port uint16
+	// Packed bool fields
+	Flags Flags8
+}
+
+// Flags of ConfigPackedStruct
+const (
+	ConfigDebug Flags8 = 1 << iota
+	ConfigEnabled
+)
+
+// Debug reports whether the Debug flag is set
+func (s *ConfigPackedStruct) Debug() bool { return s.Flags.Has(ConfigDebug) }
+
+// SetDebug sets or clears the Debug flag
+func (s *ConfigPackedStruct) SetDebug(on bool) { s.Flags.Set(ConfigDebug, on) }
+
+// Enabled reports whether the Enabled flag is set
+func (s *ConfigPackedStruct) Enabled() bool { return s.Flags.Has(ConfigEnabled) }
+
+// SetEnabled sets or clears the Enabled flag
+func (s *ConfigPackedStruct) SetEnabled(on bool) { s.Flags.Set(ConfigEnabled, on) }
+
+// GraphQLPackedStruct represents a bit-packed GraphQL query struct
+type GraphQLPackedStruct struct {
+	// String pointers (8 bytes)
+	QueryID   string
+	Operation string
+	ClientID  string
+	// 8-byte fields
+	Timestamp int64
+	Duration  int64
+	// 4-byte fields
+	Depth           int32
+	ComplexityScore float32
+	// 2-byte fields
+	FragmentCount uint16
+	// Packed bool fields
+	Flags Flags8
+}
+
+// Flags of GraphQLPackedStruct
+const (
+	GraphQLIsMutation Flags8 = 1 << iota
+	GraphQLHasVariables
+	GraphQLCached
+)
+
+// IsMutation reports whether the IsMutation flag is set
+func (s *GraphQLPackedStruct) IsMutation() bool { return s.Flags.Has(GraphQLIsMutation) }
+
+// SetIsMutation sets or clears the IsMutation flag
+func (s *GraphQLPackedStruct) SetIsMutation(on bool) { s.Flags.Set(GraphQLIsMutation, on) }
+
+// HasVariables reports whether the HasVariables flag is set
+func (s *GraphQLPackedStruct) HasVariables() bool { return s.Flags.Has(GraphQLHasVariables) }
+
+// SetHasVariables sets or clears the HasVariables flag
+func (s *GraphQLPackedStruct) SetHasVariables(on bool) { s.Flags.Set(GraphQLHasVariables, on) }
+
+// Cached reports whether the Cached flag is set
+func (s *GraphQLPackedStruct) Cached() bool { return s.Flags.Has(GraphQLCached) }
+
+// SetCached sets or clears the Cached flag
+func (s *GraphQLPackedStruct) SetCached(on bool) { s.Flags.Set(GraphQLCached, on) }
+
+// DBEntityPackedStruct represents a bit-packed database entity struct
+type DBEntityPackedStruct struct {
+	// String fields (pointers, 8 bytes)
+	ID    string
+	Name  string
+	Email string
+	// 8-byte fields
+	CreatedAt   time.Time // Time is larger than 8 bytes
+	UpdatedAt   time.Time
+	LastLoginAt time.Time
+	// 4-byte fields
+	LoginCount int32
+	Status     int32
+	// 2-byte fields
+	AccessLevel uint16
+	// Packed bool fields
+	Flags Flags8
+}
+
+// Flags of DBEntityPackedStruct
+const (
+	DBEntityIsActive Flags8 = 1 << iota
+	DBEntityIsAdmin
+	DBEntityHasMFA
+)
+
+// IsActive reports whether the IsActive flag is set
+func (s *DBEntityPackedStruct) IsActive() bool { return s.Flags.Has(DBEntityIsActive) }
+
+// SetIsActive sets or clears the IsActive flag
+func (s *DBEntityPackedStruct) SetIsActive(on bool) { s.Flags.Set(DBEntityIsActive, on) }
+
+// IsAdmin reports whether the IsAdmin flag is set
+func (s *DBEntityPackedStruct) IsAdmin() bool { return s.Flags.Has(DBEntityIsAdmin) }
+
+// SetIsAdmin sets or clears the IsAdmin flag
+func (s *DBEntityPackedStruct) SetIsAdmin(on bool) { s.Flags.Set(DBEntityIsAdmin, on) }
+
+// HasMFA reports whether the HasMFA flag is set
+func (s *DBEntityPackedStruct) HasMFA() bool { return s.Flags.Has(DBEntityHasMFA) }
+
+// SetHasMFA sets or clears the HasMFA flag
+func (s *DBEntityPackedStruct) SetHasMFA(on bool) { s.Flags.Set(DBEntityHasMFA, on) }
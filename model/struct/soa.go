@@ -0,0 +1,655 @@
+package model
+
+import (
+	"net"
+	"time"
+)
+
+// Struct-of-arrays definitions
+//
+// Each type stores a population of one model with a separate slice per field
+// instead of a slice of structs. Every slice holds values of a single type,
+// so there is no padding between fields, at the cost of one allocation per
+// field and reassembling a record whenever it is read as a whole.
+
+// SmallStructSoA stores a population of OptimizedStruct values as one slice per field
+type SmallStructSoA struct {
+	StringField    []string
+	StringFieldB   []string
+	SliceField     [][]int
+	MapField       []map[string]int
+	DurationField  []time.Duration
+	TimeField      []time.Time
+	Float64Field   []float64
+	Int64Field     []int64
+	Uint64Field    []uint64
+	Int64FieldB    []int64
+	InterfaceField []interface{}
+	Float32Field   []float32
+	Int32Field     []int32
+	Uint32Field    []uint32
+	Int32FieldB    []int32
+	Int16Field     []int16
+	Uint16Field    []uint16
+	Int16FieldB    []int16
+	Int8Field      []int8
+	Uint8Field     []uint8
+	BoolField      []bool
+	BoolFieldB     []bool
+	ByteField      []byte
+	RuneField      []rune
+}
+
+// NewSmallStructSoA creates a SmallStructSoA with room for capacity records in every field
+func NewSmallStructSoA(capacity int) *SmallStructSoA {
+	return &SmallStructSoA{
+		StringField:    make([]string, 0, capacity),
+		StringFieldB:   make([]string, 0, capacity),
+		SliceField:     make([][]int, 0, capacity),
+		MapField:       make([]map[string]int, 0, capacity),
+		DurationField:  make([]time.Duration, 0, capacity),
+		TimeField:      make([]time.Time, 0, capacity),
+		Float64Field:   make([]float64, 0, capacity),
+		Int64Field:     make([]int64, 0, capacity),
+		Uint64Field:    make([]uint64, 0, capacity),
+		Int64FieldB:    make([]int64, 0, capacity),
+		InterfaceField: make([]interface{}, 0, capacity),
+		Float32Field:   make([]float32, 0, capacity),
+		Int32Field:     make([]int32, 0, capacity),
+		Uint32Field:    make([]uint32, 0, capacity),
+		Int32FieldB:    make([]int32, 0, capacity),
+		Int16Field:     make([]int16, 0, capacity),
+		Uint16Field:    make([]uint16, 0, capacity),
+		Int16FieldB:    make([]int16, 0, capacity),
+		Int8Field:      make([]int8, 0, capacity),
+		Uint8Field:     make([]uint8, 0, capacity),
+		BoolField:      make([]bool, 0, capacity),
+		BoolFieldB:     make([]bool, 0, capacity),
+		ByteField:      make([]byte, 0, capacity),
+		RuneField:      make([]rune, 0, capacity),
+	}
+}
+
+// Append adds a record to the end of every field slice
+func (s *SmallStructSoA) Append(v OptimizedStruct) {
+	s.StringField = append(s.StringField, v.StringField)
+	s.StringFieldB = append(s.StringFieldB, v.StringFieldB)
+	s.SliceField = append(s.SliceField, v.SliceField)
+	s.MapField = append(s.MapField, v.MapField)
+	s.DurationField = append(s.DurationField, v.DurationField)
+	s.TimeField = append(s.TimeField, v.TimeField)
+	s.Float64Field = append(s.Float64Field, v.Float64Field)
+	s.Int64Field = append(s.Int64Field, v.Int64Field)
+	s.Uint64Field = append(s.Uint64Field, v.Uint64Field)
+	s.Int64FieldB = append(s.Int64FieldB, v.Int64FieldB)
+	s.InterfaceField = append(s.InterfaceField, v.InterfaceField)
+	s.Float32Field = append(s.Float32Field, v.Float32Field)
+	s.Int32Field = append(s.Int32Field, v.Int32Field)
+	s.Uint32Field = append(s.Uint32Field, v.Uint32Field)
+	s.Int32FieldB = append(s.Int32FieldB, v.Int32FieldB)
+	s.Int16Field = append(s.Int16Field, v.Int16Field)
+	s.Uint16Field = append(s.Uint16Field, v.Uint16Field)
+	s.Int16FieldB = append(s.Int16FieldB, v.Int16FieldB)
+	s.Int8Field = append(s.Int8Field, v.Int8Field)
+	s.Uint8Field = append(s.Uint8Field, v.Uint8Field)
+	s.BoolField = append(s.BoolField, v.BoolField)
+	s.BoolFieldB = append(s.BoolFieldB, v.BoolFieldB)
+	s.ByteField = append(s.ByteField, v.ByteField)
+	s.RuneField = append(s.RuneField, v.RuneField)
+}
+
+// At reassembles the record at index i
+func (s *SmallStructSoA) At(i int) OptimizedStruct {
+	return OptimizedStruct{
+		StringField:    s.StringField[i],
+		StringFieldB:   s.StringFieldB[i],
+		SliceField:     s.SliceField[i],
+		MapField:       s.MapField[i],
+		DurationField:  s.DurationField[i],
+		TimeField:      s.TimeField[i],
+		Float64Field:   s.Float64Field[i],
+		Int64Field:     s.Int64Field[i],
+		Uint64Field:    s.Uint64Field[i],
+		Int64FieldB:    s.Int64FieldB[i],
+		InterfaceField: s.InterfaceField[i],
+		Float32Field:   s.Float32Field[i],
+		Int32Field:     s.Int32Field[i],
+		Uint32Field:    s.Uint32Field[i],
+		Int32FieldB:    s.Int32FieldB[i],
+		Int16Field:     s.Int16Field[i],
+		Uint16Field:    s.Uint16Field[i],
+		Int16FieldB:    s.Int16FieldB[i],
+		Int8Field:      s.Int8Field[i],
+		Uint8Field:     s.Uint8Field[i],
+		BoolField:      s.BoolField[i],
+		BoolFieldB:     s.BoolFieldB[i],
+		ByteField:      s.ByteField[i],
+		RuneField:      s.RuneField[i],
+	}
+}
+
+// Len returns the number of records
+func (s *SmallStructSoA) Len() int {
+	return len(s.StringField)
+}
+
+// LargeStructSoA stores a population of LargeOptimizedStruct values as one slice per field
+type LargeStructSoA struct {
+	UserProfile       []map[string]string
+	ActivityHistory   [][]time.Time
+	Settings          []map[string]bool
+	Tags              [][]string
+	IPAddress         []net.IP
+	Listener          []net.Listener
+	Conn              []net.Conn
+	Metrics           []map[string]float64
+	CreatedAt         []time.Time
+	UpdatedAt         []time.Time
+	ExpiresAt         []time.Time
+	DueDate           []time.Time
+	Duration          []time.Duration
+	Timeout           []time.Duration
+	TransactionID     []uint64
+	UserID            []uint64
+	AccountID         []uint64
+	OrderID           []uint64
+	ParentID          []uint64
+	RequestTimestamp  []int64
+	ResponseTimestamp []int64
+	Balance           []float64
+	Credit            []float64
+	Score             []float64
+	StatusCode        []int32
+	ResponseCode      []int32
+	RequestCount      []int32
+	RetryAttempts     []int32
+	ErrorCount        []int32
+	BatchSize         []int32
+	ServiceTime       []float32
+	CPUTime           []float32
+	MemoryUsage       []float32
+	DiskUsage         []float32
+	NetworkUsage      []float32
+	Percentage        []float32
+	ErrorCode         []uint16
+	ProtocolVersion   []uint16
+	ServerRegion      []uint16
+	ClientRegion      []uint16
+	Port              []uint16
+	BackupPort        []uint16
+	IsSuccess         []bool
+	IsRetry           []bool
+	IsCached          []bool
+	IsVerified        []bool
+	IsActive          []bool
+	IsAdmin           []bool
+	IsTest            []bool
+	IsPriority        []bool
+	IsFlagged         []bool
+	Priority          []uint8
+	CompressionLevel  []uint8
+	Importance        []uint8
+	Status            []byte
+	Type              []byte
+}
+
+// NewLargeStructSoA creates a LargeStructSoA with room for capacity records in every field
+func NewLargeStructSoA(capacity int) *LargeStructSoA {
+	return &LargeStructSoA{
+		UserProfile:       make([]map[string]string, 0, capacity),
+		ActivityHistory:   make([][]time.Time, 0, capacity),
+		Settings:          make([]map[string]bool, 0, capacity),
+		Tags:              make([][]string, 0, capacity),
+		IPAddress:         make([]net.IP, 0, capacity),
+		Listener:          make([]net.Listener, 0, capacity),
+		Conn:              make([]net.Conn, 0, capacity),
+		Metrics:           make([]map[string]float64, 0, capacity),
+		CreatedAt:         make([]time.Time, 0, capacity),
+		UpdatedAt:         make([]time.Time, 0, capacity),
+		ExpiresAt:         make([]time.Time, 0, capacity),
+		DueDate:           make([]time.Time, 0, capacity),
+		Duration:          make([]time.Duration, 0, capacity),
+		Timeout:           make([]time.Duration, 0, capacity),
+		TransactionID:     make([]uint64, 0, capacity),
+		UserID:            make([]uint64, 0, capacity),
+		AccountID:         make([]uint64, 0, capacity),
+		OrderID:           make([]uint64, 0, capacity),
+		ParentID:          make([]uint64, 0, capacity),
+		RequestTimestamp:  make([]int64, 0, capacity),
+		ResponseTimestamp: make([]int64, 0, capacity),
+		Balance:           make([]float64, 0, capacity),
+		Credit:            make([]float64, 0, capacity),
+		Score:             make([]float64, 0, capacity),
+		StatusCode:        make([]int32, 0, capacity),
+		ResponseCode:      make([]int32, 0, capacity),
+		RequestCount:      make([]int32, 0, capacity),
+		RetryAttempts:     make([]int32, 0, capacity),
+		ErrorCount:        make([]int32, 0, capacity),
+		BatchSize:         make([]int32, 0, capacity),
+		ServiceTime:       make([]float32, 0, capacity),
+		CPUTime:           make([]float32, 0, capacity),
+		MemoryUsage:       make([]float32, 0, capacity),
+		DiskUsage:         make([]float32, 0, capacity),
+		NetworkUsage:      make([]float32, 0, capacity),
+		Percentage:        make([]float32, 0, capacity),
+		ErrorCode:         make([]uint16, 0, capacity),
+		ProtocolVersion:   make([]uint16, 0, capacity),
+		ServerRegion:      make([]uint16, 0, capacity),
+		ClientRegion:      make([]uint16, 0, capacity),
+		Port:              make([]uint16, 0, capacity),
+		BackupPort:        make([]uint16, 0, capacity),
+		IsSuccess:         make([]bool, 0, capacity),
+		IsRetry:           make([]bool, 0, capacity),
+		IsCached:          make([]bool, 0, capacity),
+		IsVerified:        make([]bool, 0, capacity),
+		IsActive:          make([]bool, 0, capacity),
+		IsAdmin:           make([]bool, 0, capacity),
+		IsTest:            make([]bool, 0, capacity),
+		IsPriority:        make([]bool, 0, capacity),
+		IsFlagged:         make([]bool, 0, capacity),
+		Priority:          make([]uint8, 0, capacity),
+		CompressionLevel:  make([]uint8, 0, capacity),
+		Importance:        make([]uint8, 0, capacity),
+		Status:            make([]byte, 0, capacity),
+		Type:              make([]byte, 0, capacity),
+	}
+}
+
+// Append adds a record to the end of every field slice
+func (s *LargeStructSoA) Append(v LargeOptimizedStruct) {
+	s.UserProfile = append(s.UserProfile, v.UserProfile)
+	s.ActivityHistory = append(s.ActivityHistory, v.ActivityHistory)
+	s.Settings = append(s.Settings, v.Settings)
+	s.Tags = append(s.Tags, v.Tags)
+	s.IPAddress = append(s.IPAddress, v.IPAddress)
+	s.Listener = append(s.Listener, v.Listener)
+	s.Conn = append(s.Conn, v.Conn)
+	s.Metrics = append(s.Metrics, v.Metrics)
+	s.CreatedAt = append(s.CreatedAt, v.CreatedAt)
+	s.UpdatedAt = append(s.UpdatedAt, v.UpdatedAt)
+	s.ExpiresAt = append(s.ExpiresAt, v.ExpiresAt)
+	s.DueDate = append(s.DueDate, v.DueDate)
+	s.Duration = append(s.Duration, v.Duration)
+	s.Timeout = append(s.Timeout, v.Timeout)
+	s.TransactionID = append(s.TransactionID, v.TransactionID)
+	s.UserID = append(s.UserID, v.UserID)
+	s.AccountID = append(s.AccountID, v.AccountID)
+	s.OrderID = append(s.OrderID, v.OrderID)
+	s.ParentID = append(s.ParentID, v.ParentID)
+	s.RequestTimestamp = append(s.RequestTimestamp, v.RequestTimestamp)
+	s.ResponseTimestamp = append(s.ResponseTimestamp, v.ResponseTimestamp)
+	s.Balance = append(s.Balance, v.Balance)
+	s.Credit = append(s.Credit, v.Credit)
+	s.Score = append(s.Score, v.Score)
+	s.StatusCode = append(s.StatusCode, v.StatusCode)
+	s.ResponseCode = append(s.ResponseCode, v.ResponseCode)
+	s.RequestCount = append(s.RequestCount, v.RequestCount)
+	s.RetryAttempts = append(s.RetryAttempts, v.RetryAttempts)
+	s.ErrorCount = append(s.ErrorCount, v.ErrorCount)
+	s.BatchSize = append(s.BatchSize, v.BatchSize)
+	s.ServiceTime = append(s.ServiceTime, v.ServiceTime)
+	s.CPUTime = append(s.CPUTime, v.CPUTime)
+	s.MemoryUsage = append(s.MemoryUsage, v.MemoryUsage)
+	s.DiskUsage = append(s.DiskUsage, v.DiskUsage)
+	s.NetworkUsage = append(s.NetworkUsage, v.NetworkUsage)
+	s.Percentage = append(s.Percentage, v.Percentage)
+	s.ErrorCode = append(s.ErrorCode, v.ErrorCode)
+	s.ProtocolVersion = append(s.ProtocolVersion, v.ProtocolVersion)
+	s.ServerRegion = append(s.ServerRegion, v.ServerRegion)
+	s.ClientRegion = append(s.ClientRegion, v.ClientRegion)
+	s.Port = append(s.Port, v.Port)
+	s.BackupPort = append(s.BackupPort, v.BackupPort)
+	s.IsSuccess = append(s.IsSuccess, v.IsSuccess)
+	s.IsRetry = append(s.IsRetry, v.IsRetry)
+	s.IsCached = append(s.IsCached, v.IsCached)
+	s.IsVerified = append(s.IsVerified, v.IsVerified)
+	s.IsActive = append(s.IsActive, v.IsActive)
+	s.IsAdmin = append(s.IsAdmin, v.IsAdmin)
+	s.IsTest = append(s.IsTest, v.IsTest)
+	s.IsPriority = append(s.IsPriority, v.IsPriority)
+	s.IsFlagged = append(s.IsFlagged, v.IsFlagged)
+	s.Priority = append(s.Priority, v.Priority)
+	s.CompressionLevel = append(s.CompressionLevel, v.CompressionLevel)
+	s.Importance = append(s.Importance, v.Importance)
+	s.Status = append(s.Status, v.Status)
+	s.Type = append(s.Type, v.Type)
+}
+
+// At reassembles the record at index i
+func (s *LargeStructSoA) At(i int) LargeOptimizedStruct {
+	return LargeOptimizedStruct{
+		UserProfile:       s.UserProfile[i],
+		ActivityHistory:   s.ActivityHistory[i],
+		Settings:          s.Settings[i],
+		Tags:              s.Tags[i],
+		IPAddress:         s.IPAddress[i],
+		Listener:          s.Listener[i],
+		Conn:              s.Conn[i],
+		Metrics:           s.Metrics[i],
+		CreatedAt:         s.CreatedAt[i],
+		UpdatedAt:         s.UpdatedAt[i],
+		ExpiresAt:         s.ExpiresAt[i],
+		DueDate:           s.DueDate[i],
+		Duration:          s.Duration[i],
+		Timeout:           s.Timeout[i],
+		TransactionID:     s.TransactionID[i],
+		UserID:            s.UserID[i],
+		AccountID:         s.AccountID[i],
+		OrderID:           s.OrderID[i],
+		ParentID:          s.ParentID[i],
+		RequestTimestamp:  s.RequestTimestamp[i],
+		ResponseTimestamp: s.ResponseTimestamp[i],
+		Balance:           s.Balance[i],
+		Credit:            s.Credit[i],
+		Score:             s.Score[i],
+		StatusCode:        s.StatusCode[i],
+		ResponseCode:      s.ResponseCode[i],
+		RequestCount:      s.RequestCount[i],
+		RetryAttempts:     s.RetryAttempts[i],
+		ErrorCount:        s.ErrorCount[i],
+		BatchSize:         s.BatchSize[i],
+		ServiceTime:       s.ServiceTime[i],
+		CPUTime:           s.CPUTime[i],
+		MemoryUsage:       s.MemoryUsage[i],
+		DiskUsage:         s.DiskUsage[i],
+		NetworkUsage:      s.NetworkUsage[i],
+		Percentage:        s.Percentage[i],
+		ErrorCode:         s.ErrorCode[i],
+		ProtocolVersion:   s.ProtocolVersion[i],
+		ServerRegion:      s.ServerRegion[i],
+		ClientRegion:      s.ClientRegion[i],
+		Port:              s.Port[i],
+		BackupPort:        s.BackupPort[i],
+		IsSuccess:         s.IsSuccess[i],
+		IsRetry:           s.IsRetry[i],
+		IsCached:          s.IsCached[i],
+		IsVerified:        s.IsVerified[i],
+		IsActive:          s.IsActive[i],
+		IsAdmin:           s.IsAdmin[i],
+		IsTest:            s.IsTest[i],
+		IsPriority:        s.IsPriority[i],
+		IsFlagged:         s.IsFlagged[i],
+		Priority:          s.Priority[i],
+		CompressionLevel:  s.CompressionLevel[i],
+		Importance:        s.Importance[i],
+		Status:            s.Status[i],
+		Type:              s.Type[i],
+	}
+}
+
+// Len returns the number of records
+func (s *LargeStructSoA) Len() int {
+	return len(s.UserProfile)
+}
+
+// APIStructSoA stores a population of APIOptimizedStruct values as one slice per field
+type APIStructSoA struct {
+	RequestID     []uint64
+	UserID        []uint64
+	Timestamp     []int64
+	SessionID     []uint64
+	StatusCode    []int32
+	Latency       []float32
+	APIVersion    []uint16
+	Method        []byte
+	Authenticated []bool
+	Cached        []bool
+}
+
+// NewAPIStructSoA creates a APIStructSoA with room for capacity records in every field
+func NewAPIStructSoA(capacity int) *APIStructSoA {
+	return &APIStructSoA{
+		RequestID:     make([]uint64, 0, capacity),
+		UserID:        make([]uint64, 0, capacity),
+		Timestamp:     make([]int64, 0, capacity),
+		SessionID:     make([]uint64, 0, capacity),
+		StatusCode:    make([]int32, 0, capacity),
+		Latency:       make([]float32, 0, capacity),
+		APIVersion:    make([]uint16, 0, capacity),
+		Method:        make([]byte, 0, capacity),
+		Authenticated: make([]bool, 0, capacity),
+		Cached:        make([]bool, 0, capacity),
+	}
+}
+
+// Append adds a record to the end of every field slice
+func (s *APIStructSoA) Append(v APIOptimizedStruct) {
+	s.RequestID = append(s.RequestID, v.RequestID)
+	s.UserID = append(s.UserID, v.UserID)
+	s.Timestamp = append(s.Timestamp, v.Timestamp)
+	s.SessionID = append(s.SessionID, v.SessionID)
+	s.StatusCode = append(s.StatusCode, v.StatusCode)
+	s.Latency = append(s.Latency, v.Latency)
+	s.APIVersion = append(s.APIVersion, v.APIVersion)
+	s.Method = append(s.Method, v.Method)
+	s.Authenticated = append(s.Authenticated, v.Authenticated)
+	s.Cached = append(s.Cached, v.Cached)
+}
+
+// At reassembles the record at index i
+func (s *APIStructSoA) At(i int) APIOptimizedStruct {
+	return APIOptimizedStruct{
+		RequestID:     s.RequestID[i],
+		UserID:        s.UserID[i],
+		Timestamp:     s.Timestamp[i],
+		SessionID:     s.SessionID[i],
+		StatusCode:    s.StatusCode[i],
+		Latency:       s.Latency[i],
+		APIVersion:    s.APIVersion[i],
+		Method:        s.Method[i],
+		Authenticated: s.Authenticated[i],
+		Cached:        s.Cached[i],
+	}
+}
+
+// Len returns the number of records
+func (s *APIStructSoA) Len() int {
+	return len(s.RequestID)
+}
+
+// ConfigStructSoA stores a population of ConfigOptimizedStruct values as one slice per field
+type ConfigStructSoA struct {
+	Name           []string
+	Description    []string
+	Environment    []string
+	UpdatedAt      []int64
+	CreatedAt      []int64
+	MaxConnections []int32
+	Timeout        []int32
+	Port           []uint16
+	Debug          []bool
+	Enabled        []bool
+}
+
+// NewConfigStructSoA creates a ConfigStructSoA with room for capacity records in every field
+func NewConfigStructSoA(capacity int) *ConfigStructSoA {
+	return &ConfigStructSoA{
+		Name:           make([]string, 0, capacity),
+		Description:    make([]string, 0, capacity),
+		Environment:    make([]string, 0, capacity),
+		UpdatedAt:      make([]int64, 0, capacity),
+		CreatedAt:      make([]int64, 0, capacity),
+		MaxConnections: make([]int32, 0, capacity),
+		Timeout:        make([]int32, 0, capacity),
+		Port:           make([]uint16, 0, capacity),
+		Debug:          make([]bool, 0, capacity),
+		Enabled:        make([]bool, 0, capacity),
+	}
+}
+
+// Append adds a record to the end of every field slice
+func (s *ConfigStructSoA) Append(v ConfigOptimizedStruct) {
+	s.Name = append(s.Name, v.Name)
+	s.Description = append(s.Description, v.Description)
+	s.Environment = append(s.Environment, v.Environment)
+	s.UpdatedAt = append(s.UpdatedAt, v.UpdatedAt)
+	s.CreatedAt = append(s.CreatedAt, v.CreatedAt)
+	s.MaxConnections = append(s.MaxConnections, v.MaxConnections)
+	s.Timeout = append(s.Timeout, v.Timeout)
+	s.Port = append(s.Port, v.Port)
+	s.Debug = append(s.Debug, v.Debug)
+	s.Enabled = append(s.Enabled, v.Enabled)
+}
+
+// At reassembles the record at index i
+func (s *ConfigStructSoA) At(i int) ConfigOptimizedStruct {
+	return ConfigOptimizedStruct{
+		Name:           s.Name[i],
+		Description:    s.Description[i],
+		Environment:    s.Environment[i],
+		UpdatedAt:      s.UpdatedAt[i],
+		CreatedAt:      s.CreatedAt[i],
+		MaxConnections: s.MaxConnections[i],
+		Timeout:        s.Timeout[i],
+		Port:           s.Port[i],
+		Debug:          s.Debug[i],
+		Enabled:        s.Enabled[i],
+	}
+}
+
+// Len returns the number of records
+func (s *ConfigStructSoA) Len() int {
+	return len(s.Name)
+}
+
+// GraphQLStructSoA stores a population of GraphQLOptimizedStruct values as one slice per field
+type GraphQLStructSoA struct {
+	QueryID         []string
+	Operation       []string
+	ClientID        []string
+	Timestamp       []int64
+	Duration        []int64
+	Depth           []int32
+	ComplexityScore []float32
+	FragmentCount   []uint16
+	IsMutation      []bool
+	HasVariables    []bool
+	Cached          []bool
+}
+
+// NewGraphQLStructSoA creates a GraphQLStructSoA with room for capacity records in every field
+func NewGraphQLStructSoA(capacity int) *GraphQLStructSoA {
+	return &GraphQLStructSoA{
+		QueryID:         make([]string, 0, capacity),
+		Operation:       make([]string, 0, capacity),
+		ClientID:        make([]string, 0, capacity),
+		Timestamp:       make([]int64, 0, capacity),
+		Duration:        make([]int64, 0, capacity),
+		Depth:           make([]int32, 0, capacity),
+		ComplexityScore: make([]float32, 0, capacity),
+		FragmentCount:   make([]uint16, 0, capacity),
+		IsMutation:      make([]bool, 0, capacity),
+		HasVariables:    make([]bool, 0, capacity),
+		Cached:          make([]bool, 0, capacity),
+	}
+}
+
+// Append adds a record to the end of every field slice
+func (s *GraphQLStructSoA) Append(v GraphQLOptimizedStruct) {
+	s.QueryID = append(s.QueryID, v.QueryID)
+	s.Operation = append(s.Operation, v.Operation)
+	s.ClientID = append(s.ClientID, v.ClientID)
+	s.Timestamp = append(s.Timestamp, v.Timestamp)
+	s.Duration = append(s.Duration, v.Duration)
+	s.Depth = append(s.Depth, v.Depth)
+	s.ComplexityScore = append(s.ComplexityScore, v.ComplexityScore)
+	s.FragmentCount = append(s.FragmentCount, v.FragmentCount)
+	s.IsMutation = append(s.IsMutation, v.IsMutation)
+	s.HasVariables = append(s.HasVariables, v.HasVariables)
+	s.Cached = append(s.Cached, v.Cached)
+}
+
+// At reassembles the record at index i
+func (s *GraphQLStructSoA) At(i int) GraphQLOptimizedStruct {
+	return GraphQLOptimizedStruct{
+		QueryID:         s.QueryID[i],
+		Operation:       s.Operation[i],
+		ClientID:        s.ClientID[i],
+		Timestamp:       s.Timestamp[i],
+		Duration:        s.Duration[i],
+		Depth:           s.Depth[i],
+		ComplexityScore: s.ComplexityScore[i],
+		FragmentCount:   s.FragmentCount[i],
+		IsMutation:      s.IsMutation[i],
+		HasVariables:    s.HasVariables[i],
+		Cached:          s.Cached[i],
+	}
+}
+
+// Len returns the number of records
+func (s *GraphQLStructSoA) Len() int {
+	return len(s.QueryID)
+}
+
+// DBEntityStructSoA stores a population of DBEntityOptimizedStruct values as one slice per field
+type DBEntityStructSoA struct {
+	ID          []string
+	Name        []string
+	Email       []string
+	CreatedAt   []time.Time
+	UpdatedAt   []time.Time
+	LastLoginAt []time.Time
+	LoginCount  []int32
+	Status      []int32
+	AccessLevel []uint16
+	IsActive    []bool
+	IsAdmin     []bool
+	HasMFA      []bool
+}
+
+// NewDBEntityStructSoA creates a DBEntityStructSoA with room for capacity records in every field
+func NewDBEntityStructSoA(capacity int) *DBEntityStructSoA {
+	return &DBEntityStructSoA{
+		ID:          make([]string, 0, capacity),
+		Name:        make([]string, 0, capacity),
+		Email:       make([]string, 0, capacity),
+		CreatedAt:   make([]time.Time, 0, capacity),
+		UpdatedAt:   make([]time.Time, 0, capacity),
+		LastLoginAt: make([]time.Time, 0, capacity),
+		LoginCount:  make([]int32, 0, capacity),
+		Status:      make([]int32, 0, capacity),
+		AccessLevel: make([]uint16, 0, capacity),
+		IsActive:    make([]bool, 0, capacity),
+		IsAdmin:     make([]bool, 0, capacity),
+		HasMFA:      make([]bool, 0, capacity),
+	}
+}
+
+// Append adds a record to the end of every field slice
+func (s *DBEntityStructSoA) Append(v DBEntityOptimizedStruct) {
+	s.ID = append(s.ID, v.ID)
+	s.Name = append(s.Name, v.Name)
+	s.Email = append(s.Email, v.Email)
+	s.CreatedAt = append(s.CreatedAt, v.CreatedAt)
+	s.UpdatedAt = append(s.UpdatedAt, v.UpdatedAt)
+	s.LastLoginAt = append(s.LastLoginAt, v.LastLoginAt)
+	s.LoginCount = append(s.LoginCount, v.LoginCount)
+	s.Status = append(s.Status, v.Status)
+	s.AccessLevel = append(s.AccessLevel, v.AccessLevel)
+	s.IsActive = append(s.IsActive, v.IsActive)
+	s.IsAdmin = append(s.IsAdmin, v.IsAdmin)
+	s.HasMFA = append(s.HasMFA, v.HasMFA)
+}
+
+// At reassembles the record at index i
+func (s *DBEntityStructSoA) At(i int) DBEntityOptimizedStruct {
+	return DBEntityOptimizedStruct{
+		ID:          s.ID[i],
+		Name:        s.Name[i],
+		Email:       s.Email[i],
+		CreatedAt:   s.CreatedAt[i],
+		UpdatedAt:   s.UpdatedAt[i],
+		LastLoginAt: s.LastLoginAt[i],
+		LoginCount:  s.LoginCount[i],
+		Status:      s.Status[i],
+		AccessLevel: s.AccessLevel[i],
+		IsActive:    s.IsActive[i],
+		IsAdmin:     s.IsAdmin[i],
+		HasMFA:      s.HasMFA[i],
+	}
+}
+
+// Len returns the number of records
+func (s *DBEntityStructSoA) Len() int {
+	return len(s.ID)
+}
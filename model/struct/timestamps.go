@@ -0,0 +1,324 @@
+package model
+
+import (
+	"math"
+	"net"
+	"time"
+)
+
+// Compact timestamp struct definitions
+//
+// These mirror the optimized structs that hold time.Time values, but store
+// each timestamp either as int64 nanoseconds since the Unix epoch or as int32
+// seconds since TimestampEpoch. Neither keeps a location or a monotonic
+// clock reading, and the zero value of both stands for the zero time.Time.
+
+// TimestampEpoch is the instant EpochSeconds values are counted from
+var TimestampEpoch = time.Date(2020, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// UnixNanos is a timestamp stored as nanoseconds since the Unix epoch.
+// It covers the years 1678 to 2262 with full precision.
+type UnixNanos int64
+
+// NewUnixNanos converts t to a UnixNanos timestamp
+func NewUnixNanos(t time.Time) UnixNanos {
+	if t.IsZero() {
+		return 0
+	}
+	return UnixNanos(t.UnixNano())
+}
+
+// Time converts the timestamp back to a UTC time.Time
+func (u UnixNanos) Time() time.Time {
+	if u == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, int64(u)).UTC()
+}
+
+// EpochSeconds is a timestamp stored as whole seconds since TimestampEpoch.
+// It drops sub-second precision and covers roughly 68 years either side of
+// the epoch; times outside that range are clamped.
+type EpochSeconds int32
+
+// NewEpochSeconds converts t to an EpochSeconds timestamp
+func NewEpochSeconds(t time.Time) EpochSeconds {
+	if t.IsZero() {
+		return 0
+	}
+	offset := t.Unix() - TimestampEpoch.Unix()
+	return EpochSeconds(min(max(offset, math.MinInt32), math.MaxInt32))
+}
+
+// Time converts the timestamp back to a UTC time.Time
+func (e EpochSeconds) Time() time.Time {
+	if e == 0 {
+		return time.Time{}
+	}
+	return time.Unix(TimestampEpoch.Unix()+int64(e), 0).UTC()
+}
+
+// SmallUnixNanosStruct represents a small struct with an int64 nanosecond timestamp
+type SmallUnixNanosStruct struct {
+	// String fields (pointers - 8 bytes or 16 bytes on 64-bit systems)
+	StringField  string
+	StringFieldB string
+
+	// Slices (pointers + length + capacity - 24 bytes on 64-bit systems)
+	SliceField []int
+	MapField   map[string]int
+
+	// Complex data types
+	DurationField time.Duration // 8 bytes (int64)
+	TimeField     UnixNanos     // 8 bytes
+
+	// 8-byte aligned fields
+	Float64Field float64 // 8 bytes
+	Int64Field   int64   // 8 bytes
+	Uint64Field  uint64  // 8 bytes
+	Int64FieldB  int64   // 8 bytes
+
+	// Interface (16 bytes typically)
+	InterfaceField interface{}
+
+	// 4-byte aligned fields
+	Float32Field float32 // 4 bytes
+	Int32Field   int32   // 4 bytes
+	Uint32Field  uint32  // 4 bytes
+	Int32FieldB  int32   // 4 bytes
+
+	// 2-byte aligned fields
+	Int16Field  int16  // 2 bytes
+	Uint16Field uint16 // 2 bytes
+	Int16FieldB int16  // 2 bytes
+
+	// 1-byte fields at the end
+	Int8Field  int8  // 1 byte
+	Uint8Field uint8 // 1 byte
+	BoolField  bool  // 1 byte
+	BoolFieldB bool  // 1 byte
+	ByteField  byte  // 1 byte
+	RuneField  rune  // technically 4 bytes (alias for int32)
+}
+
+// SmallEpochSecondsStruct represents a small struct with an int32 seconds-offset timestamp
+type SmallEpochSecondsStruct struct {
+	// String fields (pointers - 8 bytes or 16 bytes on 64-bit systems)
+	StringField  string
+	StringFieldB string
+
+	// Slices (pointers + length + capacity - 24 bytes on 64-bit systems)
+	SliceField []int
+	MapField   map[string]int
+
+	// Complex data types
+	DurationField time.Duration // 8 bytes (int64)
+
+	// 8-byte aligned fields
+	Float64Field float64 // 8 bytes
+	Int64Field   int64   // 8 bytes
+	Uint64Field  uint64  // 8 bytes
+	Int64FieldB  int64   // 8 bytes
+
+	// Interface (16 bytes typically)
+	InterfaceField interface{}
+
+	// 4-byte aligned fields
+	TimeField    EpochSeconds // 4 bytes
+	Float32Field float32      // 4 bytes
+	Int32Field   int32        // 4 bytes
+	Uint32Field  uint32       // 4 bytes
+	Int32FieldB  int32        // 4 bytes
+
+	// 2-byte aligned fields
+	Int16Field  int16  // 2 bytes
+	Uint16Field uint16 // 2 bytes
+	Int16FieldB int16  // 2 bytes
+
+	// 1-byte fields at the end
+	Int8Field  int8  // 1 byte
+	Uint8Field uint8 // 1 byte
+	BoolField  bool  // 1 byte
+	BoolFieldB bool  // 1 byte
+	ByteField  byte  // 1 byte
+	RuneField  rune  // technically 4 bytes (alias for int32)
+}
+
+// LargeUnixNanosStruct represents a large struct with int64 nanosecond timestamps
+type LargeUnixNanosStruct struct {
+	// Complex fields with pointers and internal structures
+	UserProfile     map[string]string  // Map is a pointer
+	ActivityHistory []UnixNanos        // Slice is a pointer + len + cap
+	Settings        map[string]bool    // Another map
+	Tags            []string           // Slice of strings
+	IPAddress       net.IP             // IP address (slice under the hood)
+	Listener        net.Listener       // Interface (pointer + type data)
+	Conn            net.Conn           // Another interface
+	Metrics         map[string]float64 // Map of metrics
+
+	// 8-byte aligned fields
+	CreatedAt         UnixNanos // 8 bytes
+	UpdatedAt         UnixNanos
+	ExpiresAt         UnixNanos
+	DueDate           UnixNanos
+	Duration          time.Duration // 8 bytes (int64)
+	Timeout           time.Duration
+	TransactionID     uint64
+	UserID            uint64
+	AccountID         uint64
+	OrderID           uint64
+	ParentID          uint64
+	RequestTimestamp  int64
+	ResponseTimestamp int64
+	Balance           float64
+	Credit            float64
+	Score             float64
+
+	// 4-byte fields
+	StatusCode    int32
+	ResponseCode  int32
+	RequestCount  int32
+	RetryAttempts int32
+	ErrorCount    int32
+	BatchSize     int32
+	ServiceTime   float32
+	CPUTime       float32
+	MemoryUsage   float32
+	DiskUsage     float32
+	NetworkUsage  float32
+	Percentage    float32
+
+	// 2-byte fields
+	ErrorCode       uint16
+	ProtocolVersion uint16
+	ServerRegion    uint16
+	ClientRegion    uint16
+	Port            uint16
+	BackupPort      uint16
+
+	// 1-byte fields
+	IsSuccess        bool
+	IsRetry          bool
+	IsCached         bool
+	IsVerified       bool
+	IsActive         bool
+	IsAdmin          bool
+	IsTest           bool
+	IsPriority       bool
+	IsFlagged        bool
+	Priority         uint8
+	CompressionLevel uint8
+	Importance       uint8
+	Status           byte
+	Type             byte
+}
+
+// LargeEpochSecondsStruct represents a large struct with int32 seconds-offset timestamps
+type LargeEpochSecondsStruct struct {
+	// Complex fields with pointers and internal structures
+	UserProfile     map[string]string  // Map is a pointer
+	ActivityHistory []EpochSeconds     // Slice is a pointer + len + cap
+	Settings        map[string]bool    // Another map
+	Tags            []string           // Slice of strings
+	IPAddress       net.IP             // IP address (slice under the hood)
+	Listener        net.Listener       // Interface (pointer + type data)
+	Conn            net.Conn           // Another interface
+	Metrics         map[string]float64 // Map of metrics
+
+	// 8-byte aligned fields
+	Duration          time.Duration // 8 bytes (int64)
+	Timeout           time.Duration
+	TransactionID     uint64
+	UserID            uint64
+	AccountID         uint64
+	OrderID           uint64
+	ParentID          uint64
+	RequestTimestamp  int64
+	ResponseTimestamp int64
+	Balance           float64
+	Credit            float64
+	Score             float64
+
+	// 4-byte fields
+	CreatedAt     EpochSeconds // 4 bytes
+	UpdatedAt     EpochSeconds
+	ExpiresAt     EpochSeconds
+	DueDate       EpochSeconds
+	StatusCode    int32
+	ResponseCode  int32
+	RequestCount  int32
+	RetryAttempts int32
+	ErrorCount    int32
+	BatchSize     int32
+	ServiceTime   float32
+	CPUTime       float32
+	MemoryUsage   float32
+	DiskUsage     float32
+	NetworkUsage  float32
+	Percentage    float32
+
+	// 2-byte fields
+	ErrorCode       uint16
+	ProtocolVersion uint16
+	ServerRegion    uint16
+	ClientRegion    uint16
+	Port            uint16
+	BackupPort      uint16
+
+	// 1-byte fields
+	IsSuccess        bool
+	IsRetry          bool
+	IsCached         bool
+	IsVerified       bool
+	IsActive         bool
+	IsAdmin          bool
+	IsTest           bool
+	IsPriority       bool
+	IsFlagged        bool
+	Priority         uint8
+	CompressionLevel uint8
+	Importance       uint8
+	Status           byte
+	Type             byte
+}
+
+// DBEntityUnixNanosStruct represents a database entity with int64 nanosecond timestamps
+type DBEntityUnixNanosStruct struct {
+	// String fields (pointers, 8 bytes)
+	ID    string
+	Name  string
+	Email string
+	// 8-byte fields
+	CreatedAt   UnixNanos
+	UpdatedAt   UnixNanos
+	LastLoginAt UnixNanos
+	// 4-byte fields
+	LoginCount int32
+	Status     int32
+	// 2-byte fields
+	AccessLevel uint16
+	// 1-byte fields
+	IsActive bool
+	IsAdmin  bool
+	HasMFA   bool
+}
+
+// DBEntityEpochSecondsStruct represents a database entity with int32 seconds-offset timestamps
+type DBEntityEpochSecondsStruct struct {
+	// String fields (pointers, 8 bytes)
+	ID    string
+	Name  string
+	Email string
+	// 4-byte fields
+	CreatedAt   EpochSeconds
+	UpdatedAt   EpochSeconds
+	LastLoginAt EpochSeconds
+	LoginCount  int32
+	Status      int32
+	// 2-byte fields
+	AccessLevel uint16
+	// 1-byte fields
+	IsActive bool
+	IsAdmin  bool
+	HasMFA   bool
+}
@@ -0,0 +1,120 @@
+// Package alloc provides allocation strategies for large populations of
+// same-typed objects.
+package alloc
+
+import (
+	"fmt"
+	"math"
+)
+
+// DefaultChunkSize is the number of objects in each slab chunk when none is given
+const DefaultChunkSize = 4096
+
+// Handle refers to an object in a Slab by index.
+// Unlike a pointer, a handle does not have to be scanned by the garbage collector.
+type Handle uint32
+
+// maxObjects is the number of objects a slab can hold before it runs out of handles
+const maxObjects = math.MaxUint32 + 1
+
+// Slab is a typed allocator that stores objects in fixed-size []T chunks.
+// Each chunk is a single heap object, so a population of n objects costs
+// n/chunkSize allocations instead of n. Chunks never move, so pointers
+// returned by Get stay valid until the object is freed.
+type Slab[T any] struct {
+	chunkSize int
+	chunks    [][]T
+	length    int
+	free      []Handle
+
+	// freed has a bit set for every slot on the free list, so a handle that
+	// is freed twice is caught instead of being handed out twice
+	freed []uint64
+}
+
+// NewSlab creates a slab allocator with the given chunk size.
+// A chunk size of zero or less selects DefaultChunkSize.
+func NewSlab[T any](chunkSize int) *Slab[T] {
+	if chunkSize <= 0 {
+		chunkSize = DefaultChunkSize
+	}
+	return &Slab[T]{chunkSize: chunkSize}
+}
+
+// Alloc reserves a zeroed object, reusing a freed slot when one is
+// available, and returns its handle along with a pointer to it. It fails
+// once every handle is in use.
+func (s *Slab[T]) Alloc() (Handle, *T, error) {
+	if n := len(s.free); n > 0 {
+		h := s.free[n-1]
+		s.free = s.free[:n-1]
+		s.freed[h/64] &^= 1 << (h % 64)
+		return h, s.Get(h), nil
+	}
+
+	if uint64(s.length) >= maxObjects {
+		return 0, nil, fmt.Errorf("slab is out of handles after %d objects", s.length)
+	}
+	if s.length == len(s.chunks)*s.chunkSize {
+		s.chunks = append(s.chunks, make([]T, s.chunkSize))
+	}
+
+	h := Handle(s.length)
+	s.length++
+	return h, s.Get(h), nil
+}
+
+// Put stores v in a newly allocated object and returns its handle
+func (s *Slab[T]) Put(v T) (Handle, error) {
+	h, obj, err := s.Alloc()
+	if err != nil {
+		return 0, err
+	}
+	*obj = v
+	return h, nil
+}
+
+// Get returns a pointer to the object with the given handle
+func (s *Slab[T]) Get(h Handle) *T {
+	return &s.chunks[int(h)/s.chunkSize][int(h)%s.chunkSize]
+}
+
+// Free zeroes the object with the given handle and makes its slot available
+// to the next Alloc. The handle must not be used afterwards. Freeing a handle
+// that was never allocated or is already free fails and leaves the slab as it was.
+func (s *Slab[T]) Free(h Handle) error {
+	if uint64(h) >= uint64(s.length) {
+		return fmt.Errorf("slab handle %d was never allocated", h)
+	}
+	word, bit := int(h/64), uint64(1)<<(h%64)
+	if word >= len(s.freed) {
+		s.freed = append(s.freed, make([]uint64, word+1-len(s.freed))...)
+	}
+	if s.freed[word]&bit != 0 {
+		return fmt.Errorf("slab handle %d is already free", h)
+	}
+
+	var zero T
+	*s.Get(h) = zero
+	s.freed[word] |= bit
+	s.free = append(s.free, h)
+	return nil
+}
+
+// Len returns the number of live objects in the slab
+func (s *Slab[T]) Len() int {
+	return s.length - len(s.free)
+}
+
+// Chunks returns the number of chunks allocated so far
+func (s *Slab[T]) Chunks() int {
+	return len(s.chunks)
+}
+
+// Reset releases all chunks and invalidates every handle
+func (s *Slab[T]) Reset() {
+	s.chunks = nil
+	s.length = 0
+	s.free = nil
+	s.freed = nil
+}
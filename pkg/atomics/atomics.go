@@ -0,0 +1,298 @@
+// Package atomics finds the struct fields a package accesses with sync/atomic
+// by reading its source, so their alignment can be checked before the fields
+// are reordered.
+package atomics
+
+import (
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/importer"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// funcs64 are the sync/atomic functions that operate on 64-bit integers
+var funcs64 = map[string]bool{
+	"AddInt64": true, "AddUint64": true,
+	"LoadInt64": true, "LoadUint64": true,
+	"StoreInt64": true, "StoreUint64": true,
+	"SwapInt64": true, "SwapUint64": true,
+	"CompareAndSwapInt64": true, "CompareAndSwapUint64": true,
+	"AndInt64": true, "AndUint64": true,
+	"OrInt64": true, "OrUint64": true,
+}
+
+// types64 are the sync/atomic types that wrap 64-bit integers
+var types64 = map[string]bool{
+	"Int64":  true,
+	"Uint64": true,
+}
+
+// Usage is one place a struct field is used atomically
+type Usage struct {
+	// Struct and Field name the field
+	Struct string
+	Field  string
+
+	// File and Line locate the call or field declaration
+	File string
+	Line int
+
+	// Via is the atomic function called on the field, or the atomic type
+	// the field is declared with
+	Via string
+}
+
+// Position returns the file:line location of the usage
+func (u Usage) Position() string {
+	return fmt.Sprintf("%s:%d", u.File, u.Line)
+}
+
+// String formats the usage for display
+func (u Usage) String() string {
+	return fmt.Sprintf("%s.%s via %s at %s", u.Struct, u.Field, u.Via, u.Position())
+}
+
+// Report holds the atomic field usages found in one or more packages
+type Report struct {
+	Usages []Usage
+}
+
+// Scan finds the atomic field usages in the given packages.
+// It must be run from inside the module so the package sources can be located.
+func Scan(pkgPaths ...string) (*Report, error) {
+	if len(pkgPaths) == 0 {
+		return &Report{}, nil
+	}
+
+	args := append([]string{"list", "-f", "{{.Dir}}"}, pkgPaths...)
+	cmd := exec.Command("go", args...)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	output, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("go list failed: %v\n%s", err, stderr.String())
+	}
+
+	report := &Report{}
+	for _, dir := range strings.Fields(string(output)) {
+		dirReport, err := ScanDir(dir)
+		if err != nil {
+			return nil, err
+		}
+		report.Usages = append(report.Usages, dirReport.Usages...)
+	}
+	return report, nil
+}
+
+// ScanDir finds the atomic field usages in the Go files of one package
+// directory, ignoring tests
+func ScanDir(dir string) (*Report, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return nil, err
+	}
+
+	fset := token.NewFileSet()
+	var files []*ast.File
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+
+	// Resolve the type of the value a field is selected from, so that a call
+	// is only attributed to the struct that declares the field. Imports the
+	// source importer cannot resolve are reported as type errors, which leave
+	// the structs of this package resolved.
+	info := &types.Info{Selections: make(map[*ast.SelectorExpr]*types.Selection)}
+	conf := types.Config{
+		Importer: importer.ForCompiler(fset, "source", nil),
+		Error:    func(error) {},
+	}
+	var pkg *types.Package
+	if len(files) > 0 {
+		pkg, _ = conf.Check(files[0].Name.Name, fset, files, info)
+	}
+
+	report := &Report{}
+	for _, file := range files {
+		name := atomicImport(file)
+		if name == "" {
+			continue
+		}
+		ast.Inspect(file, func(n ast.Node) bool {
+			switch n := n.(type) {
+			case *ast.TypeSpec:
+				st, ok := n.Type.(*ast.StructType)
+				if !ok {
+					return true
+				}
+				for _, field := range st.Fields.List {
+					if !isSelector(field.Type, name, types64) {
+						continue
+					}
+					for _, ident := range field.Names {
+						report.Usages = append(report.Usages, Usage{
+							Struct: n.Name.Name,
+							Field:  ident.Name,
+							File:   fset.Position(ident.Pos()).Filename,
+							Line:   fset.Position(ident.Pos()).Line,
+							Via:    name + "." + field.Type.(*ast.SelectorExpr).Sel.Name,
+						})
+					}
+				}
+			case *ast.CallExpr:
+				if len(n.Args) == 0 || !isSelector(n.Fun, name, funcs64) {
+					return true
+				}
+				sel, ok := addressedField(n.Args[0])
+				if !ok {
+					return true
+				}
+				structName, ok := fieldOwner(info.Selections[sel], pkg)
+				if !ok {
+					return true
+				}
+				pos := fset.Position(n.Pos())
+				report.Usages = append(report.Usages, Usage{
+					Struct: structName,
+					Field:  sel.Sel.Name,
+					File:   pos.Filename,
+					Line:   pos.Line,
+					Via:    name + "." + n.Fun.(*ast.SelectorExpr).Sel.Name,
+				})
+			}
+			return true
+		})
+	}
+
+	return report, nil
+}
+
+// Fields returns the names of the fields of a struct that are used
+// atomically, sorted and without duplicates
+func (r *Report) Fields(structName string) []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, u := range r.Usages {
+		if u.Struct == structName && !seen[u.Field] {
+			seen[u.Field] = true
+			names = append(names, u.Field)
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// ByStruct returns the usages of the fields of one struct
+func (r *Report) ByStruct(structName string) []Usage {
+	var usages []Usage
+	for _, u := range r.Usages {
+		if u.Struct == structName {
+			usages = append(usages, u)
+		}
+	}
+	return usages
+}
+
+// atomicImport returns the name sync/atomic is imported as in a file, or
+// an empty string if the file does not import it
+func atomicImport(file *ast.File) string {
+	for _, imp := range file.Imports {
+		path, err := strconv.Unquote(imp.Path.Value)
+		if err != nil || path != "sync/atomic" {
+			continue
+		}
+		if imp.Name != nil {
+			return imp.Name.Name
+		}
+		return "atomic"
+	}
+	return ""
+}
+
+// addressedField returns the selector of an argument of the form &x.Field
+func addressedField(arg ast.Expr) (*ast.SelectorExpr, bool) {
+	unary, ok := arg.(*ast.UnaryExpr)
+	if !ok || unary.Op != token.AND {
+		return nil, false
+	}
+	sel, ok := unary.X.(*ast.SelectorExpr)
+	return sel, ok
+}
+
+// fieldOwner returns the name of the struct of pkg that declares the field
+// selected by sel, following embedded structs for promoted fields. It fails
+// if the selection is not a 64-bit integer field of a named struct of pkg,
+// or could not be resolved.
+func fieldOwner(sel *types.Selection, pkg *types.Package) (string, bool) {
+	if sel == nil || sel.Kind() != types.FieldVal || !is64BitInt(sel.Obj().Type()) {
+		return "", false
+	}
+
+	owner := sel.Recv()
+	path := sel.Index()
+	for _, i := range path[:len(path)-1] {
+		st, ok := deref(owner).Underlying().(*types.Struct)
+		if !ok {
+			return "", false
+		}
+		owner = st.Field(i).Type()
+	}
+
+	named, ok := deref(owner).(*types.Named)
+	if !ok || named.Obj().Pkg() != pkg {
+		return "", false
+	}
+	return named.Obj().Name(), true
+}
+
+// deref returns the type a pointer type points to, or t itself
+func deref(t types.Type) types.Type {
+	if ptr, ok := t.Underlying().(*types.Pointer); ok {
+		return ptr.Elem()
+	}
+	return t
+}
+
+// is64BitInt reports whether t is an int64 or uint64, or a type defined on one
+func is64BitInt(t types.Type) bool {
+	basic, ok := t.Underlying().(*types.Basic)
+	return ok && (basic.Kind() == types.Int64 || basic.Kind() == types.Uint64)
+}
+
+// isIdent reports whether expr is one of the given identifiers
+func isIdent(expr ast.Expr, names ...string) bool {
+	ident, ok := expr.(*ast.Ident)
+	if !ok {
+		return false
+	}
+	for _, name := range names {
+		if ident.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// isSelector reports whether expr is pkg.Name for one of the given names
+func isSelector(expr ast.Expr, pkg string, names map[string]bool) bool {
+	sel, ok := expr.(*ast.SelectorExpr)
+	if !ok {
+		return false
+	}
+	return isIdent(sel.X, pkg) && names[sel.Sel.Name]
+}
@@ -0,0 +1,384 @@
+// Package escape runs the compiler's escape analysis on test packages and
+// attributes each decision to the function it was made in.
+package escape
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Kind is the outcome of one escape analysis decision
+type Kind string
+
+const (
+	// MovedToHeap means a local variable was expected on the stack but had to
+	// be moved to the heap, usually because its address outlives the function
+	MovedToHeap Kind = "moved to heap"
+
+	// Escapes means a value, such as an allocation or an interface
+	// conversion, escapes to the heap
+	Escapes Kind = "escapes to heap"
+
+	// NoEscape means a value or parameter stays on the stack
+	NoEscape Kind = "does not escape"
+
+	// Leaks means a parameter, or what it points to, leaks to the caller or the heap
+	Leaks Kind = "leaking param"
+)
+
+// Decision is one escape analysis decision reported by the compiler
+type Decision struct {
+	// Package is the import path of the package the decision was made in
+	Package string
+
+	// Function is the top-level function or method the decision was made in
+	Function string
+
+	// File, Line and Column locate the value in the source
+	File   string
+	Line   int
+	Column int
+
+	// Kind is the outcome of the decision
+	Kind Kind
+
+	// Subject is the expression or variable the decision is about
+	Subject string
+}
+
+// Position returns the decision's location as file:line:column
+func (d Decision) Position() string {
+	return fmt.Sprintf("%s:%d:%d", d.File, d.Line, d.Column)
+}
+
+// String returns the decision in the compiler's wording
+func (d Decision) String() string {
+	if d.Kind == MovedToHeap {
+		return fmt.Sprintf("%s: moved to heap: %s", d.Position(), d.Subject)
+	}
+	return fmt.Sprintf("%s: %s %s", d.Position(), d.Subject, d.Kind)
+}
+
+// Report holds the escape analysis decisions for a set of packages
+type Report struct {
+	Decisions []Decision
+
+	// refs maps each package to the names each of its top-level functions
+	// refers to, which Reachable follows
+	refs map[string]map[string][]string
+}
+
+// diagnosticLine matches a compiler diagnostic of the form file:line:col: message
+var diagnosticLine = regexp.MustCompile(`^(.+\.go):(\d+):(\d+): (.*)$`)
+
+// Analyze builds the given packages with -gcflags=-m=2 using the local go
+// toolchain and collects the final escape decision for every value.
+// It must be run from inside the module so the package sources are available.
+func Analyze(pkgPaths ...string) (*Report, error) {
+	if len(pkgPaths) == 0 {
+		return &Report{}, nil
+	}
+
+	args := append([]string{"build", "-gcflags=-m=2"}, pkgPaths...)
+	cmd := exec.Command("go", args...)
+	var output bytes.Buffer
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+
+	// The diagnostics are written to stderr, so a failed build is only an
+	// error when nothing could be parsed from it
+	runErr := cmd.Run()
+
+	report, err := parse(&output)
+	if err != nil {
+		return nil, err
+	}
+	if runErr != nil && len(report.Decisions) == 0 {
+		return nil, fmt.Errorf("go build -gcflags=-m=2 failed: %v\n%s", runErr, output.String())
+	}
+
+	return report, nil
+}
+
+// parse reads compiler output and attributes each decision to its function
+func parse(output *bytes.Buffer) (*Report, error) {
+	report := &Report{refs: make(map[string]map[string][]string)}
+	funcs := make(map[string][]funcRange)
+	seen := make(map[Decision]bool)
+
+	var pkg string
+	scanner := bufio.NewScanner(output)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "# ") {
+			pkg = strings.TrimPrefix(line, "# ")
+			continue
+		}
+
+		m := diagnosticLine.FindStringSubmatch(line)
+		if m == nil {
+			continue
+		}
+
+		decision, ok := parseMessage(m[4])
+		if !ok {
+			continue
+		}
+		decision.Package = pkg
+		decision.File = m[1]
+		decision.Line, _ = strconv.Atoi(m[2])
+		decision.Column, _ = strconv.Atoi(m[3])
+
+		// Parse the whole package the first time one of its files is seen,
+		// so that calls through files without decisions are followed too
+		if _, parsed := funcs[decision.File]; !parsed {
+			if err := report.parsePackage(pkg, filepath.Dir(decision.File), funcs); err != nil {
+				return nil, err
+			}
+		}
+		decision.Function = enclosingFunc(funcs[decision.File], decision.Line)
+
+		// -m=2 repeats some decisions, for instance once per inlined call site
+		if !seen[decision] {
+			seen[decision] = true
+			report.Decisions = append(report.Decisions, decision)
+		}
+	}
+
+	return report, scanner.Err()
+}
+
+// parseMessage extracts the kind and subject from a diagnostic message.
+// Explanation lines, which are indented or end in a colon, are skipped so
+// that only the final decision for each value is kept.
+func parseMessage(msg string) (Decision, bool) {
+	if strings.HasPrefix(msg, " ") || strings.HasSuffix(msg, ":") {
+		return Decision{}, false
+	}
+
+	switch {
+	case strings.HasPrefix(msg, "moved to heap: "):
+		return Decision{Kind: MovedToHeap, Subject: strings.TrimPrefix(msg, "moved to heap: ")}, true
+	case strings.HasPrefix(msg, "leaking param"):
+		return Decision{Kind: Leaks, Subject: msg}, true
+	case strings.HasSuffix(msg, " escapes to heap"):
+		return Decision{Kind: Escapes, Subject: strings.TrimSuffix(msg, " escapes to heap")}, true
+	case strings.HasSuffix(msg, " does not escape"):
+		return Decision{Kind: NoEscape, Subject: strings.TrimSuffix(msg, " does not escape")}, true
+	}
+	return Decision{}, false
+}
+
+// funcRange is the span of lines a top-level function declaration covers
+type funcRange struct {
+	name       string
+	start, end int
+
+	// refs are the identifiers the function's body refers to. Names selected
+	// from a value, which may be methods, are prefixed with a dot.
+	refs []string
+}
+
+// parsePackage parses the Go files of a package directory, ignoring tests,
+// records the function ranges of each file in funcs and the references
+// between the package's functions in the report
+func (r *Report) parsePackage(pkgPath, dir string, funcs map[string][]funcRange) error {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.go"))
+	if err != nil {
+		return err
+	}
+
+	refs := make(map[string][]string)
+	for _, path := range paths {
+		if strings.HasSuffix(path, "_test.go") {
+			continue
+		}
+		ranges, err := parseFuncRanges(path)
+		if err != nil {
+			return err
+		}
+		funcs[path] = ranges
+		for _, fr := range ranges {
+			refs[fr.name] = append(refs[fr.name], fr.refs...)
+		}
+	}
+	r.refs[pkgPath] = refs
+	return nil
+}
+
+// parseFuncRanges returns the line spans of the top-level functions in a
+// file, with the identifiers each one refers to
+func parseFuncRanges(path string) ([]funcRange, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, path, nil, parser.SkipObjectResolution)
+	if err != nil {
+		return nil, err
+	}
+
+	var ranges []funcRange
+	for _, decl := range file.Decls {
+		fn, ok := decl.(*ast.FuncDecl)
+		if !ok {
+			continue
+		}
+		ranges = append(ranges, funcRange{
+			name:  funcName(fn),
+			start: fset.Position(fn.Pos()).Line,
+			end:   fset.Position(fn.End()).Line,
+			refs:  funcRefs(fn),
+		})
+	}
+	return ranges, nil
+}
+
+// funcRefs returns the identifiers a function's body refers to, including
+// functions passed as values rather than called. Names selected from a value
+// are prefixed with a dot, since without type information any method of that
+// name may be meant.
+func funcRefs(fn *ast.FuncDecl) []string {
+	var refs []string
+	var visit func(ast.Node) bool
+	visit = func(n ast.Node) bool {
+		switch n := n.(type) {
+		case *ast.SelectorExpr:
+			refs = append(refs, "."+n.Sel.Name)
+			ast.Inspect(n.X, visit)
+			return false
+		case *ast.Ident:
+			refs = append(refs, n.Name)
+		}
+		return true
+	}
+	if fn.Body != nil {
+		ast.Inspect(fn.Body, visit)
+	}
+	return refs
+}
+
+// funcName formats a function declaration the way the compiler does,
+// for example testAPIOptimized or (*StructOrderTest).Run
+func funcName(fn *ast.FuncDecl) string {
+	if fn.Recv == nil || len(fn.Recv.List) == 0 {
+		return fn.Name.Name
+	}
+
+	recv := fn.Recv.List[0].Type
+	pointer := false
+	if star, ok := recv.(*ast.StarExpr); ok {
+		pointer = true
+		recv = star.X
+	}
+
+	// Drop type parameters from generic receivers
+	switch r := recv.(type) {
+	case *ast.IndexExpr:
+		recv = r.X
+	case *ast.IndexListExpr:
+		recv = r.X
+	}
+
+	name := fmt.Sprint(recv)
+	if ident, ok := recv.(*ast.Ident); ok {
+		name = ident.Name
+	}
+	if pointer {
+		return fmt.Sprintf("(*%s).%s", name, fn.Name.Name)
+	}
+	return fmt.Sprintf("%s.%s", name, fn.Name.Name)
+}
+
+// enclosingFunc returns the name of the function covering a line, or an
+// empty string for package-level code
+func enclosingFunc(ranges []funcRange, line int) string {
+	for _, r := range ranges {
+		if line >= r.start && line <= r.end {
+			return r.name
+		}
+	}
+	return ""
+}
+
+// Reachable returns a report holding only the decisions made in the functions
+// of a package that the entry functions, named as in Decision.Function, can
+// reach by calling or referring to other functions of the package. A name
+// selected from a value reaches every method of that name, so the result may
+// include more methods than are actually called, but never misses one.
+func (r *Report) Reachable(pkgPath string, entries ...string) *Report {
+	refs := r.refs[pkgPath]
+
+	// Methods are referred to by their bare name
+	methods := make(map[string][]string)
+	for name := range refs {
+		if i := strings.LastIndex(name, "."); i >= 0 {
+			methods[name[i+1:]] = append(methods[name[i+1:]], name)
+		}
+	}
+
+	reached := make(map[string]bool)
+	queue := append([]string(nil), entries...)
+	for len(queue) > 0 {
+		name := queue[0]
+		queue = queue[1:]
+		if reached[name] {
+			continue
+		}
+		reached[name] = true
+		for _, ref := range refs[name] {
+			if method, ok := strings.CutPrefix(ref, "."); ok {
+				queue = append(queue, methods[method]...)
+			} else if _, ok := refs[ref]; ok {
+				queue = append(queue, ref)
+			}
+		}
+	}
+
+	sub := &Report{refs: r.refs}
+	for _, d := range r.Decisions {
+		if d.Package == pkgPath && reached[d.Function] {
+			sub.Decisions = append(sub.Decisions, d)
+		}
+	}
+	return sub
+}
+
+// ByFunction groups the decisions made in a package by function
+func (r *Report) ByFunction(pkgPath string) map[string][]Decision {
+	functions := make(map[string][]Decision)
+	for _, d := range r.Decisions {
+		if d.Package == pkgPath && d.Function != "" {
+			functions[d.Function] = append(functions[d.Function], d)
+		}
+	}
+	return functions
+}
+
+// MovedToHeap returns the variables in a package that were moved to the
+// heap, sorted by position
+func (r *Report) MovedToHeap(pkgPath string) []Decision {
+	var moved []Decision
+	for _, d := range r.Decisions {
+		if d.Package == pkgPath && d.Kind == MovedToHeap {
+			moved = append(moved, d)
+		}
+	}
+
+	sort.Slice(moved, func(i, j int) bool {
+		if moved[i].File != moved[j].File {
+			return moved[i].File < moved[j].File
+		}
+		return moved[i].Line < moved[j].Line
+	})
+	return moved
+}
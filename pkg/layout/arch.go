@@ -0,0 +1,96 @@
+package layout
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// wordSizes maps the architectures a layout can be computed for to their pointer size
+var wordSizes = map[string]uintptr{
+	"386":   4,
+	"arm":   4,
+	"amd64": 8,
+	"arm64": 8,
+}
+
+// Archs32 are the 32-bit architectures, where 64-bit values are only
+// aligned to 4 bytes unless the compiler is told otherwise
+var Archs32 = []string{"386", "arm"}
+
+// OfArch returns the layout a struct type would have when compiled for arch.
+// It follows the gc compiler's rules, including the 8-byte alignment of
+// sync/atomic.Int64 and Uint64 on 32-bit architectures.
+func OfArch(t reflect.Type, arch string) (Layout, error) {
+	word, ok := wordSizes[arch]
+	if !ok {
+		return Layout{}, fmt.Errorf("unsupported architecture %q", arch)
+	}
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	size, align := sizeAlign(t, word)
+	l := Layout{Type: t, Size: size, Align: align}
+	if t.Kind() != reflect.Struct {
+		return l, nil
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		size, align := sizeAlign(f.Type, word)
+		l.Fields = append(l.Fields, Field{Name: f.Name, Type: f.Type, Size: size, Align: align})
+	}
+	l.Fields = Reorder(l.Fields)
+	for _, f := range l.Fields {
+		l.Padding += f.Padding
+	}
+	return l, nil
+}
+
+// sizeAlign returns the size and alignment of t on an architecture with the given word size
+func sizeAlign(t reflect.Type, word uintptr) (size, align uintptr) {
+	switch t.Kind() {
+	case reflect.Bool, reflect.Int8, reflect.Uint8:
+		return 1, 1
+	case reflect.Int16, reflect.Uint16:
+		return 2, 2
+	case reflect.Int32, reflect.Uint32, reflect.Float32:
+		return 4, 4
+	case reflect.Complex64:
+		// A complex64 is two float32s, so it is 4-byte aligned everywhere
+		return 8, 4
+	case reflect.Int64, reflect.Uint64, reflect.Float64:
+		// 64-bit values are only word aligned on 32-bit architectures
+		return 8, min(8, word)
+	case reflect.Complex128:
+		return 16, min(8, word)
+	case reflect.Int, reflect.Uint, reflect.Uintptr, reflect.Pointer, reflect.UnsafePointer,
+		reflect.Map, reflect.Chan, reflect.Func:
+		return word, word
+	case reflect.String, reflect.Interface:
+		return 2 * word, word
+	case reflect.Slice:
+		return 3 * word, word
+	case reflect.Array:
+		elemSize, elemAlign := sizeAlign(t.Elem(), word)
+		return uintptr(t.Len()) * elemSize, elemAlign
+	case reflect.Struct:
+		if isAlign64(t) {
+			return 0, 8
+		}
+		fields := make([]Field, t.NumField())
+		align = 1
+		for i := range fields {
+			fields[i].Size, fields[i].Align = sizeAlign(t.Field(i).Type, word)
+			align = max(align, fields[i].Align)
+		}
+		return SizeOf(fields), align
+	}
+	return t.Size(), uintptr(t.Align())
+}
+
+// isAlign64 reports whether t is the marker type sync/atomic uses to make the
+// compiler align Int64 and Uint64 to 8 bytes on every architecture
+func isAlign64(t reflect.Type) bool {
+	return t.Name() == "align64" && t.PkgPath() == "sync/atomic"
+}
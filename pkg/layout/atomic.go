@@ -0,0 +1,160 @@
+package layout
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// AtomicCheck is the result of checking that the 64-bit fields a struct
+// accesses atomically stay 8-byte aligned on a 32-bit architecture
+type AtomicCheck struct {
+	// Arch is the architecture the offsets were computed for
+	Arch string
+
+	// Fields are the 64-bit fields accessed atomically, in declaration order
+	Fields []string
+
+	// Misaligned are the fields that are not 8-byte aligned in the current
+	// declaration order, with their offsets on Arch
+	Misaligned []Field
+
+	// Size is the size of the struct on Arch as declared. Only the first
+	// element of a slice or array is 8-byte aligned, so unless Size is a
+	// multiple of 8 the atomic fields of every other element are misaligned,
+	// whatever their offset.
+	Size uintptr
+
+	// ReorderMisaligned are the fields the plain optimal order, which
+	// ignores atomic access, would misalign. If it is not empty the reorder
+	// suggestion must be rejected.
+	ReorderMisaligned []Field
+
+	// ReorderSize is the size on Arch of the struct in the plain optimal order
+	ReorderSize uintptr
+
+	// SafeOrder is an order with the atomic fields first, which keeps them
+	// aligned, and the other fields in the optimal order
+	SafeOrder []Field
+
+	// OptimalSize and SafeSize are the sizes on the host architecture of
+	// the struct in the plain optimal order and in SafeOrder
+	OptimalSize uintptr
+	SafeSize    uintptr
+}
+
+// ReorderRejected reports whether the optimal order would break atomic
+// access, either by misaligning an atomic field or by making a struct whose
+// size kept slice elements aligned lose that
+func (c AtomicCheck) ReorderRejected() bool {
+	return len(c.ReorderMisaligned) > 0 || (c.Size%8 == 0 && c.ReorderSize%8 != 0)
+}
+
+// Err reports the atomic fields that are misaligned on Arch as declared,
+// either within the struct or in the elements of a slice of it. Reordering
+// cannot fix the latter: the fields must be declared as atomic.Int64 or
+// atomic.Uint64, which make the compiler align the whole struct to 8 bytes,
+// or the struct padded to a multiple of 8 bytes.
+func (c AtomicCheck) Err() error {
+	var problems []string
+	for _, f := range c.Misaligned {
+		problems = append(problems, fmt.Sprintf("%s is at offset %d", f.Name, f.Offset))
+	}
+	if len(c.Fields) > 0 && c.Size%8 != 0 {
+		problems = append(problems, fmt.Sprintf(
+			"the struct is %d bytes, not a multiple of 8, so %s are misaligned in every other element of a slice",
+			c.Size, strings.Join(c.Fields, ", ")))
+	}
+	if len(problems) == 0 {
+		return nil
+	}
+	return fmt.Errorf("64-bit atomic access is misaligned on %s: %s", c.Arch, strings.Join(problems, "; "))
+}
+
+// CheckAtomicAlignment checks that the named fields, which the caller knows
+// to be accessed with sync/atomic, are 8-byte aligned on arch both in the
+// current order and in the optimal order. Fields that are not 64 bits wide
+// are ignored, since atomics on them only need their natural alignment.
+func (l Layout) CheckAtomicAlignment(atomicFields []string, arch string) (AtomicCheck, error) {
+	optimal := SortFields(l.Fields)
+	check := AtomicCheck{Arch: arch, OptimalSize: SizeOf(optimal)}
+
+	archLayout, err := OfArch(l.Type, arch)
+	if err != nil {
+		return check, err
+	}
+	check.Size = archLayout.Size
+
+	wanted := make(map[string]bool, len(atomicFields))
+	for _, name := range atomicFields {
+		wanted[name] = true
+	}
+	atomic := make(map[string]bool)
+	for _, f := range l.Fields {
+		if wanted[f.Name] && is64Bit(f.Type) {
+			atomic[f.Name] = true
+			check.Fields = append(check.Fields, f.Name)
+		}
+	}
+	if len(check.Fields) == 0 {
+		check.SafeOrder = optimal
+		check.SafeSize = check.OptimalSize
+		return check, nil
+	}
+
+	check.Misaligned = misaligned(archLayout.Fields, atomic)
+
+	// Apply the host's optimal order to the fields as laid out on arch
+	byName := make(map[string]Field, len(archLayout.Fields))
+	for _, f := range archLayout.Fields {
+		byName[f.Name] = f
+	}
+	var reordered []Field
+	for _, f := range optimal {
+		reordered = append(reordered, byName[f.Name])
+	}
+	reordered = Reorder(reordered)
+	check.ReorderMisaligned = misaligned(reordered, atomic)
+	check.ReorderSize = SizeOf(reordered)
+
+	check.SafeOrder = Reorder(SafeOrder(l.Fields, atomic))
+	check.SafeSize = SizeOf(check.SafeOrder)
+	return check, nil
+}
+
+// SafeOrder returns the fields in the optimal order, except that the fields
+// accessed atomically come first. The first word of an allocated struct is
+// 64-bit aligned on every architecture, so a run of 64-bit fields at the start
+// stays aligned. In a slice that only holds for every element if the size of
+// the struct is a multiple of 8, which AtomicCheck.Err reports.
+func SafeOrder(fields []Field, atomic map[string]bool) []Field {
+	order := SortFields(fields)
+	sort.SliceStable(order, func(i, j int) bool {
+		return atomic[order[i].Name] && !atomic[order[j].Name]
+	})
+	return order
+}
+
+// misaligned returns the atomic fields whose offset is not a multiple of 8
+func misaligned(fields []Field, atomic map[string]bool) []Field {
+	var bad []Field
+	for _, f := range fields {
+		if atomic[f.Name] && f.Offset%8 != 0 {
+			bad = append(bad, f)
+		}
+	}
+	return bad
+}
+
+// is64Bit reports whether t is a 64-bit integer or one of the 64-bit
+// integer types of sync/atomic
+func is64Bit(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Int64, reflect.Uint64:
+		return true
+	case reflect.Struct:
+		return t.PkgPath() == "sync/atomic" && (t.Name() == "Int64" || t.Name() == "Uint64")
+	}
+	return false
+}
@@ -0,0 +1,76 @@
+package layout_test
+
+import (
+	"reflect"
+	"testing"
+	"unsafe"
+
+	model "mem-tests/model/struct"
+	"mem-tests/pkg/layout"
+)
+
+// plainCounters keeps its atomic counters first, but as plain uint64 fields
+// it is 44 bytes on 32-bit platforms
+type plainCounters struct {
+	Hits     uint64
+	Misses   uint64
+	Keys     []string
+	Name     string
+	Shard    int32
+	Evicting bool
+}
+
+func TestCacheStatsSliceElementsAligned(t *testing.T) {
+	// On the host, element 1 of a slice must be usable atomically
+	s := make([]model.CacheStatsOptimizedStruct, 2)
+	s[1].RecordHit()
+	if offset := uintptr(unsafe.Pointer(&s[1].Hits)) - uintptr(unsafe.Pointer(&s[0])); offset%8 != 0 {
+		t.Errorf("&s[1].Hits is at offset %d from &s[0], not 8-byte aligned", offset)
+	}
+
+	// On every 32-bit architecture, element 1's counters must be 8-byte aligned
+	typ := reflect.TypeFor[model.CacheStatsOptimizedStruct]()
+	for _, arch := range layout.Archs32 {
+		l, err := layout.OfArch(typ, arch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		for _, f := range l.Fields {
+			if f.Name != "Hits" && f.Name != "Misses" {
+				continue
+			}
+			if offset := l.Size + f.Offset; offset%8 != 0 {
+				t.Errorf("%s: s[1].%s is at offset %d, not 8-byte aligned", arch, f.Name, offset)
+			}
+		}
+
+		check, err := layout.Of(typ).CheckAtomicAlignment([]string{"Hits", "Misses"}, arch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := check.Err(); err != nil {
+			t.Errorf("%s: %v", arch, err)
+		}
+	}
+}
+
+func TestCheckAtomicAlignmentRejectsUnalignedSize(t *testing.T) {
+	for _, arch := range layout.Archs32 {
+		check, err := layout.Of(reflect.TypeFor[plainCounters]()).CheckAtomicAlignment([]string{"Hits", "Misses"}, arch)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(check.Misaligned) != 0 {
+			t.Errorf("%s: fields misaligned in the struct: %v", arch, check.Misaligned)
+		}
+		if check.Size != 44 {
+			t.Errorf("%s: size = %d, want 44", arch, check.Size)
+		}
+		if check.Err() == nil {
+			t.Errorf("%s: no error for a %d-byte struct with 64-bit atomic fields", arch, check.Size)
+		}
+		if !check.ReorderRejected() {
+			t.Errorf("%s: optimal order that moves Keys and Name first was not rejected", arch)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package layout
+
+import (
+	"reflect"
+)
+
+// BitPacking is a proposal to pack the bool fields of a struct into a bitset
+type BitPacking struct {
+	// Flags are the bool fields that would become bits, in declaration order
+	Flags []string
+
+	// Clusters are runs of bool fields that are adjacent in the current layout
+	Clusters [][]string
+
+	// SmallFlags are other one-byte fields that could share the bitset if
+	// their values fit in a few bits
+	SmallFlags []string
+
+	// BitsetType is the smallest unsigned integer type that holds every flag
+	BitsetType reflect.Type
+
+	// ReorderedSize is the size of the struct in the optimal field order
+	ReorderedSize uintptr
+
+	// PackedSize is the size of the struct in the optimal field order with
+	// the flags replaced by the bitset
+	PackedSize uintptr
+}
+
+// Saving returns the bytes saved per struct by packing over reordering alone
+func (b BitPacking) Saving() uintptr {
+	if b.PackedSize > b.ReorderedSize {
+		return 0
+	}
+	return b.ReorderedSize - b.PackedSize
+}
+
+// FlagBytes returns the bytes the flags take up as separate bool fields
+func (b BitPacking) FlagBytes() uintptr {
+	return uintptr(len(b.Flags))
+}
+
+// ProposeBitPacking finds the bool fields of a struct and computes the size
+// of the struct if they were packed into a single bitset field.
+// Packing only saves memory when the bytes it frees cross an alignment boundary,
+// so the saving is often zero even for several flags.
+func (l Layout) ProposeBitPacking() BitPacking {
+	proposal := BitPacking{ReorderedSize: l.OptimalSize(), PackedSize: l.OptimalSize()}
+
+	var rest []Field
+	var cluster []string
+	for _, f := range l.Fields {
+		switch {
+		case f.Type.Kind() == reflect.Bool:
+			proposal.Flags = append(proposal.Flags, f.Name)
+			cluster = append(cluster, f.Name)
+			continue
+		case f.Size == 1 && isInteger(f.Type.Kind()):
+			proposal.SmallFlags = append(proposal.SmallFlags, f.Name)
+		}
+
+		if len(cluster) > 1 {
+			proposal.Clusters = append(proposal.Clusters, cluster)
+		}
+		cluster = nil
+		rest = append(rest, f)
+	}
+	if len(cluster) > 1 {
+		proposal.Clusters = append(proposal.Clusters, cluster)
+	}
+
+	if len(proposal.Flags) == 0 {
+		return proposal
+	}
+
+	proposal.BitsetType = bitsetType(len(proposal.Flags))
+	rest = append(rest, Field{
+		Name:  "Flags",
+		Type:  proposal.BitsetType,
+		Size:  proposal.BitsetType.Size(),
+		Align: uintptr(proposal.BitsetType.Align()),
+	})
+	proposal.PackedSize = SizeOf(l.sortFields(rest))
+
+	return proposal
+}
+
+// bitsetType returns the smallest unsigned integer type with at least n bits
+func bitsetType(n int) reflect.Type {
+	switch {
+	case n <= 8:
+		return reflect.TypeFor[uint8]()
+	case n <= 16:
+		return reflect.TypeFor[uint16]()
+	case n <= 32:
+		return reflect.TypeFor[uint32]()
+	default:
+		return reflect.TypeFor[uint64]()
+	}
+}
+
+// isInteger reports whether a kind is a signed or unsigned integer
+func isInteger(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,212 @@
+// Package layout describes how the compiler lays out struct fields in memory:
+// their offsets, the padding between them and the size of the struct with
+// its fields in the best possible order.
+package layout
+
+import (
+	"reflect"
+	"sort"
+)
+
+// Field is one field of a struct layout
+type Field struct {
+	// Name of the field
+	Name string
+
+	// Type of the field
+	Type reflect.Type
+
+	// Offset is the number of bytes from the start of the struct
+	Offset uintptr
+
+	// Size and Align of the field's type
+	Size  uintptr
+	Align uintptr
+
+	// Padding is the number of unused bytes between this field and the next
+	// one, or the end of the struct for the last field
+	Padding uintptr
+}
+
+// Layout is the memory layout of a struct type
+type Layout struct {
+	// Type is the struct type
+	Type reflect.Type
+
+	// Size and Align of the struct
+	Size  uintptr
+	Align uintptr
+
+	// Fields in declaration order
+	Fields []Field
+
+	// Padding is the total number of unused bytes in the struct
+	Padding uintptr
+
+	// Atomic names the fields accessed with sync/atomic that the optimal
+	// order must keep first, set by PinAtomic
+	Atomic []string
+}
+
+// Of returns the layout of a struct type. Pointers to structs are dereferenced.
+func Of(t reflect.Type) Layout {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	l := Layout{
+		Type:  t,
+		Size:  t.Size(),
+		Align: uintptr(t.Align()),
+	}
+	if t.Kind() != reflect.Struct {
+		return l
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		l.Fields = append(l.Fields, Field{
+			Name:   f.Name,
+			Type:   f.Type,
+			Offset: f.Offset,
+			Size:   f.Type.Size(),
+			Align:  uintptr(f.Type.Align()),
+		})
+	}
+
+	for i := range l.Fields {
+		end := l.Size
+		if i+1 < len(l.Fields) {
+			end = l.Fields[i+1].Offset
+		}
+		l.Fields[i].Padding = end - l.Fields[i].Offset - l.Fields[i].Size
+		l.Padding += l.Fields[i].Padding
+	}
+
+	return l
+}
+
+// OfValue returns the layout of the struct type of v
+func OfValue(v any) Layout {
+	return Of(reflect.TypeOf(v))
+}
+
+// Name returns the name of the struct type
+func (l Layout) Name() string {
+	return l.Type.Name()
+}
+
+// OptimalOrder returns the fields sorted by decreasing alignment, then by
+// decreasing size, which leaves padding only at the end of the struct.
+// Fields with equal alignment and size keep their declaration order.
+// Fields pinned with PinAtomic come first.
+func (l Layout) OptimalOrder() []Field {
+	return l.sortFields(l.Fields)
+}
+
+// PinAtomic returns a copy of the layout whose optimal order keeps the named
+// fields first. Use it for fields accessed with sync/atomic when the plain
+// optimal order would misalign them on a 32-bit platform, so that every
+// size projected from the layout is for an order that is safe to apply.
+func (l Layout) PinAtomic(fields []string) Layout {
+	l.Atomic = append([]string(nil), fields...)
+	return l
+}
+
+// sortFields sorts fields into the optimal order, keeping the fields pinned
+// with PinAtomic first
+func (l Layout) sortFields(fields []Field) []Field {
+	if len(l.Atomic) == 0 {
+		return SortFields(fields)
+	}
+	atomic := make(map[string]bool, len(l.Atomic))
+	for _, name := range l.Atomic {
+		atomic[name] = true
+	}
+	return SafeOrder(fields, atomic)
+}
+
+// OptimalSize returns the size of the struct with its fields in the optimal order
+func (l Layout) OptimalSize() uintptr {
+	return SizeOf(l.OptimalOrder())
+}
+
+// SortFields returns a copy of fields sorted by decreasing alignment, then by
+// decreasing size. A zero-size field is moved to the front, since a trailing
+// zero-size field makes the compiler add padding after it.
+func SortFields(fields []Field) []Field {
+	sorted := append([]Field(nil), fields...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if (a.Size == 0) != (b.Size == 0) {
+			return a.Size == 0
+		}
+		if a.Align != b.Align {
+			return a.Align > b.Align
+		}
+		return a.Size > b.Size
+	})
+	return sorted
+}
+
+// SizeOf returns the size of a struct whose fields have the given sizes and
+// alignments, in the given order, following the compiler's layout rules
+func SizeOf(fields []Field) uintptr {
+	var offset, align uintptr = 0, 1
+	for _, f := range fields {
+		offset = alignUp(offset, f.Align)
+		offset += f.Size
+		align = max(align, f.Align)
+	}
+
+	// A final zero-size field would point past the end of the struct,
+	// so the compiler pads the struct by a byte before rounding up
+	if n := len(fields); n > 0 && fields[n-1].Size == 0 && offset > 0 {
+		offset++
+	}
+	return alignUp(offset, align)
+}
+
+// Reorder computes the layout of the given fields in the given order,
+// filling in their offsets and padding
+func Reorder(fields []Field) []Field {
+	ordered := append([]Field(nil), fields...)
+	size := SizeOf(ordered)
+
+	var offset uintptr
+	for i := range ordered {
+		offset = alignUp(offset, ordered[i].Align)
+		ordered[i].Offset = offset
+		offset += ordered[i].Size
+	}
+	for i := range ordered {
+		end := size
+		if i+1 < len(ordered) {
+			end = ordered[i+1].Offset
+		}
+		ordered[i].Padding = end - ordered[i].Offset - ordered[i].Size
+	}
+	return ordered
+}
+
+// Arrange returns the layout the struct would have with the given fields in
+// the given order, such as a reordered or narrowed variant that is not
+// declared as a type
+func (l Layout) Arrange(fields []Field) Layout {
+	a := Layout{Type: l.Type, Align: 1, Atomic: l.Atomic}
+	a.Fields = Reorder(fields)
+	a.Size = SizeOf(a.Fields)
+	for _, f := range a.Fields {
+		a.Align = max(a.Align, f.Align)
+		a.Padding += f.Padding
+	}
+	return a
+}
+
+// alignUp rounds n up to a multiple of align
+func alignUp(n, align uintptr) uintptr {
+	if align == 0 {
+		return n
+	}
+	return (n + align - 1) / align * align
+}
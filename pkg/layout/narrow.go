@@ -0,0 +1,306 @@
+package layout
+
+import (
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// DefaultHeadroom is the growth over the observed values a narrowed type
+// must leave room for, as a fraction of their magnitude. The default keeps
+// room for values twice as large as any seen.
+const DefaultHeadroom = 1.0
+
+// Reasons an observed field keeps its declared type
+const (
+	KeptAtomic       = "accessed atomically"
+	KeptNeverWritten = "never written"
+	KeptTimestamp    = "timestamp"
+	KeptID           = "identifier"
+)
+
+// fieldRange is the range of values observed in one integer field
+type fieldRange struct {
+	index    int
+	signed   bool
+	atomic   bool
+	seen     bool
+	nonZero  bool
+	min, max int64
+	umin     uint64
+	umax     uint64
+}
+
+// RangeObserver records the smallest and largest value of every integer
+// field across a population of structs
+type RangeObserver struct {
+	layout  Layout
+	ranges  []fieldRange
+	samples int
+}
+
+// NewRangeObserver creates an observer for values of the struct type of l.
+// The narrowed size is projected from l, so pass a layout pinned with
+// PinAtomic when the optimal order must keep atomic fields first. The fields
+// named in atomic are accessed with sync/atomic, whose functions take a fixed
+// width, so they keep their type. Fields of named integer types from other
+// packages, such as time.Duration, are not observed since their width is part
+// of their API.
+func NewRangeObserver(l Layout, atomic []string) *RangeObserver {
+	isAtomic := make(map[string]bool, len(atomic))
+	for _, name := range atomic {
+		isAtomic[name] = true
+	}
+
+	o := &RangeObserver{layout: l}
+	for i, f := range o.layout.Fields {
+		if !isInteger(f.Type.Kind()) || f.Type.PkgPath() != "" {
+			continue
+		}
+		o.ranges = append(o.ranges, fieldRange{
+			index:  i,
+			signed: isSigned(f.Type.Kind()),
+			atomic: isAtomic[f.Name],
+		})
+	}
+	return o
+}
+
+// Observe records the integer fields of v, which must be a struct of the
+// observer's type or a pointer to one
+func (o *RangeObserver) Observe(v any) {
+	rv := reflect.ValueOf(v)
+	for rv.Kind() == reflect.Pointer {
+		rv = rv.Elem()
+	}
+
+	for i := range o.ranges {
+		r := &o.ranges[i]
+		field := rv.Field(r.index)
+		if r.signed {
+			n := field.Int()
+			if !r.seen || n < r.min {
+				r.min = n
+			}
+			if !r.seen || n > r.max {
+				r.max = n
+			}
+			r.nonZero = r.nonZero || n != 0
+		} else {
+			n := field.Uint()
+			if !r.seen || n < r.umin {
+				r.umin = n
+			}
+			if !r.seen || n > r.umax {
+				r.umax = n
+			}
+			r.nonZero = r.nonZero || n != 0
+		}
+		r.seen = true
+	}
+	o.samples++
+}
+
+// FieldNarrowing suggests a narrower integer type for one field
+type FieldNarrowing struct {
+	// Field is the name of the field
+	Field string
+
+	// Current is the declared type and Suggested the narrowest type of the
+	// same signedness that holds every observed value
+	Current   reflect.Type
+	Suggested reflect.Type
+
+	// Min and Max are the observed extremes
+	Min string
+	Max string
+}
+
+// KeptField is an observed field left at its declared type although its
+// values would fit a narrower one
+type KeptField struct {
+	// Field is the name of the field
+	Field string
+
+	// Reason is one of the Kept constants
+	Reason string
+}
+
+// Narrowing is the result of observing a population
+type Narrowing struct {
+	// Fields that can use a narrower type, in declaration order
+	Fields []FieldNarrowing
+
+	// Kept holds the fields that are not narrowed, in declaration order
+	Kept []KeptField
+
+	// Headroom is the growth the suggested types leave room for
+	Headroom float64
+
+	// Samples is the number of structs observed
+	Samples int
+
+	// ReorderedSize is the size of the struct in the optimal field order
+	ReorderedSize uintptr
+
+	// NarrowedSize is the size of the struct in the optimal field order
+	// with every suggested narrowing applied
+	NarrowedSize uintptr
+
+	// Reordered and Narrowed are the layouts the two sizes are computed from
+	Reordered Layout
+	Narrowed  Layout
+}
+
+// Saving returns the bytes saved per struct by narrowing on top of reordering
+func (n Narrowing) Saving() uintptr {
+	if n.NarrowedSize > n.ReorderedSize {
+		return 0
+	}
+	return n.ReorderedSize - n.NarrowedSize
+}
+
+// Narrowing suggests the narrowest type for every observed field that holds
+// its values grown by headroom, and projects the size of the struct with those
+// types and its fields in the optimal order. Fields accessed atomically keep
+// their type. So do fields that were never written or hold timestamps or
+// identifiers, since the population says nothing about the values they take
+// in production. A field that only ever held one value is narrowed like any
+// other, to the type that holds that value grown by headroom.
+// The suggestions only hold for the values seen so far.
+func (o *RangeObserver) Narrowing(headroom float64) Narrowing {
+	result := Narrowing{
+		Headroom:  headroom,
+		Samples:   o.samples,
+		Reordered: o.layout.Arrange(o.layout.OptimalOrder()),
+	}
+	result.ReorderedSize = result.Reordered.Size
+
+	fields := append([]Field(nil), o.layout.Fields...)
+	for _, r := range o.ranges {
+		if !r.seen {
+			continue
+		}
+
+		f := fields[r.index]
+		if reason := keepReason(f.Name, r); reason != "" {
+			result.Kept = append(result.Kept, KeptField{Field: f.Name, Reason: reason})
+			continue
+		}
+
+		var suggested reflect.Type
+		var min, max string
+		if r.signed {
+			suggested = narrowestSigned(grow(r.min, headroom), grow(r.max, headroom))
+			min, max = strconv.FormatInt(r.min, 10), strconv.FormatInt(r.max, 10)
+		} else {
+			suggested = narrowestUnsigned(growUnsigned(r.umax, headroom))
+			min, max = strconv.FormatUint(r.umin, 10), strconv.FormatUint(r.umax, 10)
+		}
+		if suggested.Size() >= f.Size {
+			continue
+		}
+
+		result.Fields = append(result.Fields, FieldNarrowing{
+			Field:     f.Name,
+			Current:   f.Type,
+			Suggested: suggested,
+			Min:       min,
+			Max:       max,
+		})
+		fields[r.index].Type = suggested
+		fields[r.index].Size = suggested.Size()
+		fields[r.index].Align = uintptr(suggested.Align())
+	}
+
+	result.Narrowed = o.layout.Arrange(o.layout.sortFields(fields))
+	result.NarrowedSize = result.Narrowed.Size
+	return result
+}
+
+// keepReason returns why a field keeps its declared type, or "" if it may be
+// narrowed
+func keepReason(name string, r fieldRange) string {
+	switch {
+	case r.atomic:
+		return KeptAtomic
+	case !r.nonZero:
+		return KeptNeverWritten
+	case isTimestampName(name):
+		return KeptTimestamp
+	case isIDName(name):
+		return KeptID
+	}
+	return ""
+}
+
+// isTimestampName reports whether a field name reads as a point in time, such
+// as CreatedAt or RequestTimestamp. Narrowing Unix times to 32 bits breaks in 2038.
+func isTimestampName(name string) bool {
+	return strings.HasSuffix(name, "At") || strings.HasSuffix(name, "Time") || strings.HasSuffix(name, "Timestamp")
+}
+
+// isIDName reports whether a field name reads as an identifier, such as
+// UserID, whose values grow with the data rather than staying in a range
+func isIDName(name string) bool {
+	return strings.HasSuffix(name, "ID") || strings.HasSuffix(name, "Id")
+}
+
+// grow scales n away from zero by headroom, saturating at the int64 limits
+func grow(n int64, headroom float64) int64 {
+	g := float64(n) * (1 + headroom)
+	switch {
+	case g >= math.MaxInt64:
+		return math.MaxInt64
+	case g <= math.MinInt64:
+		return math.MinInt64
+	}
+	return int64(g)
+}
+
+// growUnsigned scales n by headroom, saturating at the uint64 limit
+func growUnsigned(n uint64, headroom float64) uint64 {
+	g := float64(n) * (1 + headroom)
+	if g >= math.MaxUint64 {
+		return math.MaxUint64
+	}
+	return uint64(g)
+}
+
+// narrowestSigned returns the smallest signed integer type holding min and max
+func narrowestSigned(min, max int64) reflect.Type {
+	switch {
+	case min >= math.MinInt8 && max <= math.MaxInt8:
+		return reflect.TypeFor[int8]()
+	case min >= math.MinInt16 && max <= math.MaxInt16:
+		return reflect.TypeFor[int16]()
+	case min >= math.MinInt32 && max <= math.MaxInt32:
+		return reflect.TypeFor[int32]()
+	default:
+		return reflect.TypeFor[int64]()
+	}
+}
+
+// narrowestUnsigned returns the smallest unsigned integer type holding max
+func narrowestUnsigned(max uint64) reflect.Type {
+	switch {
+	case max <= math.MaxUint8:
+		return reflect.TypeFor[uint8]()
+	case max <= math.MaxUint16:
+		return reflect.TypeFor[uint16]()
+	case max <= math.MaxUint32:
+		return reflect.TypeFor[uint32]()
+	default:
+		return reflect.TypeFor[uint64]()
+	}
+}
+
+// isSigned reports whether a kind is a signed integer
+func isSigned(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package layout
+
+import (
+	"reflect"
+)
+
+// Level is one struct in the tree of nested and embedded structs of a type
+type Level struct {
+	// Path is the dotted path of field names leading to this struct,
+	// or the type name for the outermost struct
+	Path string
+
+	// Type is the struct type at this level
+	Type reflect.Type
+
+	// Depth is 0 for the outermost struct, 1 for its struct fields and so on
+	Depth int
+
+	// Embedded reports whether the struct is an embedded field
+	Embedded bool
+
+	// Offset from the start of the outermost struct, and Size of this struct
+	Offset uintptr
+	Size   uintptr
+
+	// Padding is the total number of unused bytes between the direct fields
+	// of this struct and after the last one
+	Padding uintptr
+
+	// TailPadding is the number of unused bytes after the last field
+	TailPadding uintptr
+
+	// TrailingZeroSize is the name of a zero-size last field that makes the
+	// compiler pad the struct, or empty if there is none
+	TrailingZeroSize string
+}
+
+// Nesting describes the padding of a struct and every struct nested in it
+type Nesting struct {
+	// Levels are the outermost struct followed by its nested structs, depth first
+	Levels []Level
+
+	// PaddingByDepth is the total padding of all the structs at each depth
+	PaddingByDepth []uintptr
+
+	// NestedTailPadding is the tail padding of all the nested structs, which
+	// stays inside the outer struct and can only be reclaimed by flattening
+	NestedTailPadding uintptr
+
+	// OptimalSize is the size of the struct in the optimal field order,
+	// keeping nested structs as they are
+	OptimalSize uintptr
+
+	// FlattenedSize is the size of the struct in the optimal order with the
+	// fields of nested structs from the same package moved into it
+	FlattenedSize uintptr
+}
+
+// Reclaimable returns the bytes saved per struct by flattening over reordering alone
+func (n Nesting) Reclaimable() uintptr {
+	if n.FlattenedSize > n.OptimalSize {
+		return 0
+	}
+	return n.OptimalSize - n.FlattenedSize
+}
+
+// TrailingZeroSize returns the levels that end in a padded zero-size field
+func (n Nesting) TrailingZeroSize() []Level {
+	var levels []Level
+	for _, level := range n.Levels {
+		if level.TrailingZeroSize != "" {
+			levels = append(levels, level)
+		}
+	}
+	return levels
+}
+
+// Nesting walks the struct and every struct nested or embedded in it by
+// value, including ones from other packages such as time.Time, and totals
+// the padding at every level
+func (l Layout) Nesting() Nesting {
+	n := Nesting{OptimalSize: l.OptimalSize()}
+	n.walk(l, l.Name(), 0, 0, false)
+
+	n.FlattenedSize = SizeOf(l.sortFields(flatten(l.Fields, l.Type.PkgPath())))
+	return n
+}
+
+// walk records the level of a struct layout and descends into its struct fields
+func (n *Nesting) walk(l Layout, path string, depth int, offset uintptr, embedded bool) {
+	level := Level{
+		Path:     path,
+		Type:     l.Type,
+		Depth:    depth,
+		Embedded: embedded,
+		Offset:   offset,
+		Size:     l.Size,
+		Padding:  l.Padding,
+	}
+	if len(l.Fields) > 0 {
+		last := l.Fields[len(l.Fields)-1]
+		level.TailPadding = last.Padding
+		if last.Size == 0 && last.Padding > 0 {
+			level.TrailingZeroSize = last.Name
+		}
+	}
+
+	n.Levels = append(n.Levels, level)
+	for len(n.PaddingByDepth) <= depth {
+		n.PaddingByDepth = append(n.PaddingByDepth, 0)
+	}
+	n.PaddingByDepth[depth] += level.Padding
+	if depth > 0 {
+		n.NestedTailPadding += level.TailPadding
+	}
+
+	for i, f := range l.Fields {
+		if f.Type.Kind() != reflect.Struct {
+			continue
+		}
+		n.walk(Of(f.Type), path+"."+f.Name, depth+1, offset+f.Offset, l.Type.Field(i).Anonymous)
+	}
+}
+
+// flatten replaces every struct field declared in pkgPath with its own
+// fields, recursively. Structs from other packages are kept whole, since
+// their fields cannot be moved.
+func flatten(fields []Field, pkgPath string) []Field {
+	var flat []Field
+	for _, f := range fields {
+		foreign := f.Type.Name() != "" && f.Type.PkgPath() != pkgPath
+		if f.Type.Kind() != reflect.Struct || foreign || f.Size == 0 {
+			flat = append(flat, f)
+			continue
+		}
+		flat = append(flat, flatten(Of(f.Type).Fields, pkgPath)...)
+	}
+	return flat
+}
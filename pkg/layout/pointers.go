@@ -0,0 +1,74 @@
+package layout
+
+import (
+	"reflect"
+	"unsafe"
+)
+
+// ptrSize is the size of a pointer on the current platform
+const ptrSize = unsafe.Sizeof(uintptr(0))
+
+// PointerBytes returns the length of the prefix of a value of type t that
+// holds pointers. The collector scans an object up to its last pointer word,
+// so this is the number of bytes scanned per value, and 0 for a type the
+// collector never has to look into.
+func PointerBytes(t reflect.Type) uintptr {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Map, reflect.Chan, reflect.Func:
+		return ptrSize
+	case reflect.String, reflect.Slice:
+		// The data pointer is the first word, followed by the length
+		return ptrSize
+	case reflect.Interface:
+		// The type word is followed by the data pointer
+		return 2 * ptrSize
+	case reflect.Array:
+		elem := PointerBytes(t.Elem())
+		if t.Len() == 0 || elem == 0 {
+			return 0
+		}
+		return uintptr(t.Len()-1)*t.Elem().Size() + elem
+	case reflect.Struct:
+		var end uintptr
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if n := PointerBytes(f.Type); n > 0 {
+				end = max(end, f.Offset+n)
+			}
+		}
+		return end
+	}
+	return 0
+}
+
+// PointerWords returns the number of words in a value of type t that the
+// collector treats as pointers
+func PointerWords(t reflect.Type) int {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.UnsafePointer, reflect.Map, reflect.Chan, reflect.Func,
+		reflect.String, reflect.Slice:
+		return 1
+	case reflect.Interface:
+		// Both the type word and the data word are marked as pointers
+		return 2
+	case reflect.Array:
+		return t.Len() * PointerWords(t.Elem())
+	case reflect.Struct:
+		n := 0
+		for i := 0; i < t.NumField(); i++ {
+			n += PointerWords(t.Field(i).Type)
+		}
+		return n
+	}
+	return 0
+}
+
+// PointerBytes returns the number of bytes of the struct the collector scans
+func (l Layout) PointerBytes() uintptr {
+	return PointerBytes(l.Type)
+}
+
+// PointerWords returns the number of pointer words in the struct
+func (l Layout) PointerWords() int {
+	return PointerWords(l.Type)
+}
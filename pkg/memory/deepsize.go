@@ -0,0 +1,269 @@
+package memory
+
+import (
+	"reflect"
+	"sort"
+	"time"
+	"unsafe"
+)
+
+const (
+	// ptrSize is the size of a pointer on the current platform
+	ptrSize = uint64(unsafe.Sizeof(uintptr(0)))
+
+	// chanHeaderSize is the size of the runtime channel header (hchan)
+	chanHeaderSize = 96
+)
+
+// sharedTypes are pointer types that reference process-wide data, such as the
+// time zone a time.Time points to. Their pointees are not retained by any one value.
+var sharedTypes = map[reflect.Type]bool{
+	reflect.TypeFor[*time.Location](): true,
+}
+
+// RetainedSize reports the memory held by a value
+type RetainedSize struct {
+	// Shallow is the size of the value itself, as reported by unsafe.Sizeof
+	Shallow uint64
+
+	// Retained is the shallow size plus every byte reachable through references
+	Retained uint64
+}
+
+// Referenced returns the bytes held behind references, outside the value itself
+func (s RetainedSize) Referenced() uint64 {
+	return s.Retained - s.Shallow
+}
+
+// DeepSize walks a value with reflection and reports its shallow and retained size.
+// The retained size includes the backing bytes of strings, slice arrays, map
+// storage, boxed interface payloads and pointees. Memory reached through
+// several references, such as a shared pointer or overlapping subslices of one
+// array, is counted once. Allocator size-class rounding is not included.
+func DeepSize(v any) RetainedSize {
+	if v == nil {
+		return RetainedSize{}
+	}
+
+	rv := reflect.ValueOf(v)
+	s := &sizer{
+		seen: make(map[visit]bool),
+		refs: make(map[reflect.Type]bool),
+	}
+	s.walk(rv)
+
+	shallow := uint64(rv.Type().Size())
+	return RetainedSize{
+		Shallow:  shallow,
+		Retained: shallow + s.total,
+	}
+}
+
+// visit identifies a map or channel already accounted for
+type visit struct {
+	ptr uintptr
+	typ reflect.Type
+}
+
+// span is a range of memory addresses, from start up to but excluding end
+type span struct {
+	start, end uintptr
+}
+
+// sizer accumulates referenced bytes while walking a value graph
+type sizer struct {
+	seen  map[visit]bool
+	refs  map[reflect.Type]bool
+	total uint64
+
+	// spans are the blocks of memory accounted for, sorted and disjoint
+	spans []span
+}
+
+// mark records a map or channel and reports whether it was seen for the first time
+func (s *sizer) mark(ptr unsafe.Pointer, typ reflect.Type) bool {
+	key := visit{uintptr(ptr), typ}
+	if s.seen[key] {
+		return false
+	}
+	s.seen[key] = true
+	return true
+}
+
+// claim accounts for size bytes of memory at ptr and returns how many of them
+// were not accounted for before. Blocks are compared by address range rather
+// than start address, so a subslice, a substring or a pointer into an array
+// already counted adds nothing.
+func (s *sizer) claim(ptr unsafe.Pointer, size uint64) uint64 {
+	if size == 0 {
+		return 0
+	}
+	block := span{uintptr(ptr), uintptr(ptr) + uintptr(size)}
+	merged := block
+	fresh := size
+
+	// Merge the block with every span it overlaps or touches
+	i := sort.Search(len(s.spans), func(i int) bool { return s.spans[i].end >= block.start })
+	j := i
+	for ; j < len(s.spans) && s.spans[j].start <= block.end; j++ {
+		sp := s.spans[j]
+		if lo, hi := max(sp.start, block.start), min(sp.end, block.end); lo < hi {
+			fresh -= uint64(hi - lo)
+		}
+		merged.start = min(merged.start, sp.start)
+		merged.end = max(merged.end, sp.end)
+	}
+	s.spans = append(s.spans[:i], append([]span{merged}, s.spans[j:]...)...)
+	return fresh
+}
+
+// walk adds the bytes referenced by v. The inline size of v itself is
+// accounted for by whatever contains it.
+func (s *sizer) walk(v reflect.Value) {
+	switch v.Kind() {
+	case reflect.String:
+		if v.Len() == 0 {
+			return
+		}
+		s.total += s.claim(unsafe.Pointer(unsafe.StringData(v.String())), uint64(v.Len()))
+
+	case reflect.Slice:
+		if v.IsNil() || v.Cap() == 0 {
+			return
+		}
+		elem := v.Type().Elem()
+		fresh := s.claim(v.UnsafePointer(), uint64(v.Cap())*uint64(elem.Size()))
+		if fresh == 0 {
+			return
+		}
+		s.total += fresh
+
+		// Elements already counted through another reference are walked
+		// again, which adds nothing since everything they reference is
+		// accounted for too
+		if s.hasReferences(elem) {
+			full := v.Slice(0, v.Cap())
+			for i := 0; i < full.Len(); i++ {
+				s.walk(full.Index(i))
+			}
+		}
+
+	case reflect.Array:
+		if s.hasReferences(v.Type().Elem()) {
+			for i := 0; i < v.Len(); i++ {
+				s.walk(v.Index(i))
+			}
+		}
+
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			if s.hasReferences(v.Field(i).Type()) {
+				s.walk(v.Field(i))
+			}
+		}
+
+	case reflect.Pointer:
+		if v.IsNil() || sharedTypes[v.Type()] {
+			return
+		}
+		if fresh := s.claim(v.UnsafePointer(), uint64(v.Type().Elem().Size())); fresh > 0 {
+			s.total += fresh
+			s.walk(v.Elem())
+		}
+
+	case reflect.Interface:
+		if v.IsNil() {
+			return
+		}
+		s.walkInterface(v)
+
+	case reflect.Map:
+		if v.IsNil() {
+			return
+		}
+		if !s.mark(v.UnsafePointer(), v.Type()) {
+			return
+		}
+		s.total += MapStorageSize(v.Type(), v.Len())
+		keyRefs := s.hasReferences(v.Type().Key())
+		elemRefs := s.hasReferences(v.Type().Elem())
+		if keyRefs || elemRefs {
+			iter := v.MapRange()
+			for iter.Next() {
+				if keyRefs {
+					s.walk(iter.Key())
+				}
+				if elemRefs {
+					s.walk(iter.Value())
+				}
+			}
+		}
+
+	case reflect.Chan:
+		if v.IsNil() {
+			return
+		}
+		if s.mark(v.UnsafePointer(), v.Type()) {
+			s.total += chanHeaderSize + uint64(v.Cap())*uint64(v.Type().Elem().Size())
+		}
+	}
+}
+
+// walkInterface adds the boxed payload of a non-nil interface value
+func (s *sizer) walkInterface(v reflect.Value) {
+	elem := v.Elem()
+
+	// Pointer-shaped values are stored directly in the interface data word
+	// and zero-size values are never allocated
+	if isPointerShaped(elem.Type()) || elem.Type().Size() == 0 {
+		s.walk(elem)
+		return
+	}
+
+	// Identify the payload by the interface data word, so the same boxed
+	// value is only counted once. Copying the interface, for instance out of
+	// a map where it is not addressable, keeps the word pointing at the box.
+	box := reflect.New(v.Type()).Elem()
+	box.Set(v)
+	words := (*[2]unsafe.Pointer)(unsafe.Pointer(box.UnsafeAddr()))
+	if fresh := s.claim(words[1], uint64(elem.Type().Size())); fresh > 0 {
+		s.total += fresh
+		s.walk(elem)
+	}
+}
+
+// hasReferences reports whether values of t can point to other memory
+func (s *sizer) hasReferences(t reflect.Type) bool {
+	if has, ok := s.refs[t]; ok {
+		return has
+	}
+
+	var has bool
+	switch t.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map, reflect.Pointer,
+		reflect.Interface, reflect.Chan:
+		has = true
+	case reflect.Array:
+		has = t.Len() > 0 && s.hasReferences(t.Elem())
+	case reflect.Struct:
+		for i := 0; i < t.NumField() && !has; i++ {
+			has = s.hasReferences(t.Field(i).Type)
+		}
+	}
+
+	s.refs[t] = has
+	return has
+}
+
+// isPointerShaped reports whether a type is stored directly in an interface data word
+func isPointerShaped(t reflect.Type) bool {
+	switch t.Kind() {
+	case reflect.Pointer, reflect.Map, reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return true
+	case reflect.Struct:
+		return t.NumField() == 1 && isPointerShaped(t.Field(0).Type)
+	case reflect.Array:
+		return t.Len() == 1 && isPointerShaped(t.Elem())
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package memory
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+	"unsafe"
+)
+
+// node is a linked list element, for building cycles
+type node struct {
+	next  *node
+	value int64
+}
+
+func TestDeepSize(t *testing.T) {
+	shared := &[64]byte{}
+
+	backing := make([]int64, 10)
+
+	text := strings.Repeat("x", 100)
+
+	var box any = [4]int64{1, 2, 3, 4}
+	boxes := map[int]any{1: box, 2: box}
+
+	nodeSize := uint64(unsafe.Sizeof(node{}))
+	a := &node{value: 1}
+	b := &node{next: a, value: 2}
+	a.next = b
+
+	tests := []struct {
+		name     string
+		value    any
+		shallow  uint64
+		retained uint64
+	}{
+		{
+			name:     "scalar",
+			value:    int64(7),
+			shallow:  8,
+			retained: 8,
+		},
+		{
+			name:     "shared pointer",
+			value:    struct{ A, B *[64]byte }{shared, shared},
+			shallow:  2 * ptrSize,
+			retained: 2*ptrSize + 64,
+		},
+		{
+			name:     "overlapping subslices",
+			value:    struct{ A, B []int64 }{backing[:4], backing[2:8]},
+			shallow:  6 * ptrSize,
+			retained: 6*ptrSize + 80,
+		},
+		{
+			name:     "subslice before its array",
+			value:    struct{ A, B []int64 }{backing[2:], backing},
+			shallow:  6 * ptrSize,
+			retained: 6*ptrSize + 80,
+		},
+		{
+			name: "pointer into a slice",
+			value: struct {
+				P *int64
+				S []int64
+			}{&backing[3], backing},
+			shallow:  4 * ptrSize,
+			retained: 4*ptrSize + 80,
+		},
+		{
+			name:     "substring",
+			value:    struct{ A, B string }{text, text[10:20]},
+			shallow:  4 * ptrSize,
+			retained: 4*ptrSize + 100,
+		},
+		{
+			name:     "map holding one boxed value twice",
+			value:    boxes,
+			shallow:  ptrSize,
+			retained: ptrSize + MapStorageSize(reflect.TypeOf(boxes), len(boxes)) + 32,
+		},
+		{
+			name:     "cycle",
+			value:    a,
+			shallow:  ptrSize,
+			retained: ptrSize + 2*nodeSize,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := DeepSize(tt.value)
+			if got.Shallow != tt.shallow {
+				t.Errorf("Shallow = %d, want %d", got.Shallow, tt.shallow)
+			}
+			if got.Retained != tt.retained {
+				t.Errorf("Retained = %d, want %d", got.Retained, tt.retained)
+			}
+		})
+	}
+}
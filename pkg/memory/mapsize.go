@@ -0,0 +1,106 @@
+package memory
+
+import (
+	"math/bits"
+	"reflect"
+)
+
+// The constants below mirror the Swiss-table map implementation used since
+// Go 1.24 (internal/runtime/maps) on 64-bit platforms.
+const (
+	// mapHeaderSize is the size of the map header every map value points to
+	mapHeaderSize = 48
+
+	// mapTableSize is the size of the header of each table in the directory
+	mapTableSize = 32
+
+	// mapGroupSlots is the number of slots in a group
+	mapGroupSlots = 8
+
+	// mapCtrlWordSize is the size of the control word at the start of a group
+	mapCtrlWordSize = 8
+
+	// mapMaxTableCapacity is the slot count at which a table splits instead of growing
+	mapMaxTableCapacity = 1024
+
+	// mapMaxInlineSize is the largest key or element stored inline in a slot.
+	// Larger keys and elements are stored behind a pointer.
+	mapMaxInlineSize = 128
+)
+
+// MapCapacity estimates the number of slots a map allocates to hold the given
+// number of entries. Maps grow by doubling whenever they pass a 7/8 load factor.
+func MapCapacity(entries int) int {
+	if entries == 0 {
+		return 0
+	}
+	if entries <= mapGroupSlots {
+		return mapGroupSlots
+	}
+	needed := (entries*8 + 6) / 7
+	return 1 << bits.Len(uint(needed-1))
+}
+
+// MapStorageSize estimates the bytes allocated by a map of the given type
+// holding the given number of entries, including the header, the table
+// directory, the groups and any out-of-line keys or elements.
+func MapStorageSize(mapType reflect.Type, entries int) uint64 {
+	key, elem := mapType.Key(), mapType.Elem()
+	size := uint64(mapHeaderSize)
+
+	capacity := MapCapacity(entries)
+	if capacity == 0 {
+		return size
+	}
+
+	groups := uint64(capacity / mapGroupSlots)
+	size += groups * (mapCtrlWordSize + mapGroupSlots*mapSlotSize(key, elem))
+
+	// Maps with more than one group use a directory of tables
+	if capacity > mapGroupSlots {
+		tables := uint64(max(1, capacity/mapMaxTableCapacity))
+		size += tables*mapTableSize + tables*ptrSize
+	}
+
+	// Oversized keys and elements are allocated individually per entry
+	if key.Size() > mapMaxInlineSize {
+		size += uint64(entries) * uint64(key.Size())
+	}
+	if elem.Size() > mapMaxInlineSize {
+		size += uint64(entries) * uint64(elem.Size())
+	}
+
+	return size
+}
+
+// mapSlotSize returns the size of one key/element slot in a map group
+func mapSlotSize(key, elem reflect.Type) uint64 {
+	keySize, keyAlign := mapInlineLayout(key)
+	elemSize, elemAlign := mapInlineLayout(elem)
+
+	slotAlign := max(keyAlign, elemAlign)
+	elemOffset := alignUp(keySize, elemAlign)
+
+	// A trailing zero-size element, as in a set, still takes a byte of padding
+	// so that its address stays inside the slot
+	if elemSize == 0 && keySize > 0 {
+		elemSize = 1
+	}
+	return alignUp(elemOffset+elemSize, slotAlign)
+}
+
+// mapInlineLayout returns the size and alignment a type occupies inside a slot
+func mapInlineLayout(t reflect.Type) (uint64, uint64) {
+	if t.Size() > mapMaxInlineSize {
+		return ptrSize, ptrSize
+	}
+	return uint64(t.Size()), uint64(t.Align())
+}
+
+// alignUp rounds n up to a multiple of align
+func alignUp(n, align uint64) uint64 {
+	if align == 0 {
+		return n
+	}
+	return (n + align - 1) / align * align
+}
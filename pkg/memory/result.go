@@ -11,10 +11,33 @@ type TestResult struct {
 	// Memory difference per object (if applicable)
 	PerObjectSize float64
 
+	// Seed used to generate the test data, so the run can be reproduced
+	Seed int64
+
 	// Additional test-specific statistics
 	OtherStats map[string]any
 }
 
+// Variants names the two sides of a test's comparison. The standard result
+// keys always hold the candidate under "Optimized" and the baseline under
+// "Unoptimized". A test comparing something other than two field orders
+// stores its own names under the "Variants" key so reports label it correctly.
+type Variants struct {
+	Optimized   string
+	Unoptimized string
+}
+
+// DefaultVariants names the sides of a comparison between two field orders
+var DefaultVariants = Variants{Optimized: "Optimized", Unoptimized: "Unoptimized"}
+
+// Variants returns the names of the two sides of the result's comparison
+func (r TestResult) Variants() Variants {
+	if v, ok := r.OtherStats["Variants"].(Variants); ok {
+		return v
+	}
+	return DefaultVariants
+}
+
 // MemoryTest defines the interface for all memory tests
 type MemoryTest interface {
 	// Name returns a human-readable name for the test
@@ -23,3 +46,36 @@ type MemoryTest interface {
 	// Run executes the test and returns the results
 	Run() TestResult
 }
+
+// NewTypeResult builds the standard entry for one type in a test's "TypeResults"
+// map, comparing the memory used by the optimized and unoptimized variants.
+// Tests may add their own keys to the returned map.
+//
+// MemorySaved and PerObjectSaving are unsigned savings and are zero when the
+// optimized variant uses more memory. SavingPercent keeps its sign, so such a
+// regression still shows as a negative percentage.
+func NewTypeResult(objectCount int, optimizedMem, unoptimizedMem uint64) map[string]interface{} {
+	var memorySaved uint64
+	if unoptimizedMem > optimizedMem {
+		memorySaved = unoptimizedMem - optimizedMem
+	}
+
+	var savingPct float64
+	if unoptimizedMem > 0 {
+		savingPct = (float64(unoptimizedMem) - float64(optimizedMem)) / float64(unoptimizedMem) * 100
+	}
+
+	var perObjectSaving float64
+	if objectCount > 0 {
+		perObjectSaving = float64(memorySaved) / float64(objectCount)
+	}
+
+	return map[string]interface{}{
+		"ObjectCount":       objectCount,
+		"OptimizedMemory":   optimizedMem,
+		"UnoptimizedMemory": unoptimizedMem,
+		"MemorySaved":       memorySaved,
+		"SavingPercent":     savingPct,
+		"PerObjectSaving":   perObjectSaving,
+	}
+}
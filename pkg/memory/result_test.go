@@ -0,0 +1,30 @@
+package memory
+
+import "testing"
+
+func TestNewTypeResultSaving(t *testing.T) {
+	r := NewTypeResult(10, 600, 1000)
+	if saved := r["MemorySaved"].(uint64); saved != 400 {
+		t.Errorf("MemorySaved = %d, want 400", saved)
+	}
+	if pct := r["SavingPercent"].(float64); pct != 40 {
+		t.Errorf("SavingPercent = %.2f, want 40", pct)
+	}
+	if perObject := r["PerObjectSaving"].(float64); perObject != 40 {
+		t.Errorf("PerObjectSaving = %.2f, want 40", perObject)
+	}
+}
+
+func TestNewTypeResultRegression(t *testing.T) {
+	// The optimized variant uses more memory, which must not wrap the saving
+	r := NewTypeResult(10, 1200, 1000)
+	if saved := r["MemorySaved"].(uint64); saved != 0 {
+		t.Errorf("MemorySaved = %d, want 0", saved)
+	}
+	if pct := r["SavingPercent"].(float64); pct != -20 {
+		t.Errorf("SavingPercent = %.2f, want -20", pct)
+	}
+	if perObject := r["PerObjectSaving"].(float64); perObject != 0 {
+		t.Errorf("PerObjectSaving = %.2f, want 0", perObject)
+	}
+}
@@ -0,0 +1,45 @@
+package memory
+
+import (
+	"math/rand"
+	"time"
+)
+
+// referenceTime is the instant that generated timestamps are relative to.
+// It is fixed so that the same seed always produces the same test data.
+var referenceTime = time.Date(2025, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// timeSpread is how far before the reference time random instants fall
+const timeSpread = 365 * 24 * time.Hour
+
+// seed is the value every test generator is seeded with
+var seed = time.Now().UnixNano()
+
+// SetSeed sets the seed used by all test data generators
+func SetSeed(s int64) {
+	seed = s
+}
+
+// Seed returns the seed used by all test data generators
+func Seed() int64 {
+	return seed
+}
+
+// NewRand returns a generator seeded with the run seed.
+// Every call starts a fresh stream, so optimized and unoptimized variants
+// that consume values in the same order receive identical data.
+func NewRand() *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+// ReferenceTime returns the fixed instant that RandomTime draws timestamps before
+func ReferenceTime() time.Time {
+	return referenceTime
+}
+
+// RandomTime returns an instant within the year before the reference time.
+// It is drawn from rnd, so the same seed yields the same instants while
+// generated objects still get realistic, distinct timestamps.
+func RandomTime(rnd *rand.Rand) time.Time {
+	return referenceTime.Add(-time.Duration(rnd.Int63n(int64(timeSpread))))
+}
@@ -0,0 +1,96 @@
+package memory
+
+import (
+	"runtime"
+	"runtime/metrics"
+	"time"
+)
+
+// heapScanMetric is the runtime metric for the scannable heap at the last GC
+const heapScanMetric = "/gc/scan/heap:bytes"
+
+// Snapshot captures the cumulative allocator and collector counters at one point in time
+type Snapshot struct {
+	// TotalAlloc is the cumulative bytes allocated for heap objects
+	TotalAlloc uint64
+
+	// Mallocs is the cumulative count of heap objects allocated
+	Mallocs uint64
+
+	// Frees is the cumulative count of heap objects freed
+	Frees uint64
+
+	// HeapObjects is the number of currently allocated heap objects
+	HeapObjects uint64
+
+	// NumGC is the number of completed GC cycles
+	NumGC uint32
+
+	// PauseTotal is the cumulative time the world was stopped for GC
+	PauseTotal time.Duration
+
+	// Time is when the snapshot was taken
+	Time time.Time
+}
+
+// TakeSnapshot reads the current allocator and collector counters
+func TakeSnapshot() Snapshot {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+
+	return Snapshot{
+		TotalAlloc:  m.TotalAlloc,
+		Mallocs:     m.Mallocs,
+		Frees:       m.Frees,
+		HeapObjects: m.HeapObjects,
+		NumGC:       m.NumGC,
+		PauseTotal:  time.Duration(m.PauseTotalNs),
+		Time:        time.Now(),
+	}
+}
+
+// Delta holds the allocator and collector activity between two snapshots
+type Delta struct {
+	// TotalAlloc is the bytes allocated for heap objects
+	TotalAlloc uint64
+
+	// Mallocs is the count of heap objects allocated
+	Mallocs uint64
+
+	// Frees is the count of heap objects freed
+	Frees uint64
+
+	// NumGC is the number of GC cycles completed
+	NumGC uint32
+
+	// PauseTotal is the time the world was stopped for GC
+	PauseTotal time.Duration
+
+	// Elapsed is the wall-clock time between the snapshots
+	Elapsed time.Duration
+}
+
+// Since returns the activity between an earlier snapshot and s
+func (s Snapshot) Since(earlier Snapshot) Delta {
+	return Delta{
+		TotalAlloc: s.TotalAlloc - earlier.TotalAlloc,
+		Mallocs:    s.Mallocs - earlier.Mallocs,
+		Frees:      s.Frees - earlier.Frees,
+		NumGC:      s.NumGC - earlier.NumGC,
+		PauseTotal: s.PauseTotal - earlier.PauseTotal,
+		Elapsed:    s.Time.Sub(earlier.Time),
+	}
+}
+
+// HeapScanBytes returns how many bytes of heap the garbage collector had to
+// scan for pointers in the last cycle. Pointer-free objects are not scanned,
+// so this shows how much marking work a population causes.
+func HeapScanBytes() uint64 {
+	sample := []metrics.Sample{{Name: heapScanMetric}}
+	metrics.Read(sample)
+
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
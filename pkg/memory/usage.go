@@ -12,6 +12,24 @@ func Usage() uint64 {
 	return m.Alloc
 }
 
+// TotalAllocated returns the cumulative bytes allocated for heap objects,
+// including memory that has since been freed
+func TotalAllocated() uint64 {
+	var m runtime.MemStats
+	runtime.ReadMemStats(&m)
+	return m.TotalAlloc
+}
+
+// Growth returns how much a reading such as Usage grew from start to end.
+// A collection during a measurement can shrink the heap below start, and the
+// growth is then zero instead of a wrapped-around uint64.
+func Growth(start, end uint64) uint64 {
+	if end > start {
+		return end - start
+	}
+	return 0
+}
+
 // UsageMB returns the current memory allocation in megabytes as a float
 func UsageMB() float64 {
 	return float64(Usage()) / (1024 * 1024)
@@ -0,0 +1,179 @@
+package populate
+
+import (
+	"fmt"
+	"math/rand"
+	"sort"
+	"strings"
+)
+
+// Lengths describes the distribution of generated string lengths
+type Lengths struct {
+	// Min and Max bound the generated lengths (inclusive)
+	Min int
+	Max int
+
+	// Skewed makes most lengths fall close to Min with a long tail towards Max,
+	// like identifiers and names in real data. Otherwise lengths are uniform.
+	Skewed bool
+}
+
+// Profile describes how the reference fields of a struct are populated.
+// The zero value is the empty profile, which leaves reference fields nil.
+type Profile struct {
+	// Name identifies the profile in results
+	Name string
+
+	// StringLen is the length distribution of generated strings
+	StringLen Lengths
+
+	// SliceLen is the number of elements in each generated slice
+	SliceLen int
+
+	// MapEntries is the number of entries in each generated map
+	MapEntries int
+
+	// BoxInterfaces stores a heap-allocated value in interface fields
+	BoxInterfaces bool
+}
+
+// Profiled is implemented by tests whose data can be populated from a profile
+type Profiled interface {
+	// SetProfile selects the profile used to populate reference fields
+	SetProfile(p Profile)
+}
+
+// profiles are the predefined profiles selectable by name
+var profiles = map[string]Profile{
+	"empty": {
+		Name: "empty",
+	},
+	"small": {
+		Name:          "small",
+		StringLen:     Lengths{Min: 8, Max: 16},
+		SliceLen:      4,
+		MapEntries:    2,
+		BoxInterfaces: true,
+	},
+	"realistic": {
+		Name:          "realistic",
+		StringLen:     Lengths{Min: 8, Max: 64, Skewed: true},
+		SliceLen:      16,
+		MapEntries:    8,
+		BoxInterfaces: true,
+	},
+	"heavy": {
+		Name:          "heavy",
+		StringLen:     Lengths{Min: 32, Max: 256, Skewed: true},
+		SliceLen:      64,
+		MapEntries:    32,
+		BoxInterfaces: true,
+	},
+}
+
+// Lookup returns the predefined profile with the given name
+func Lookup(name string) (Profile, error) {
+	p, ok := profiles[strings.ToLower(name)]
+	if !ok {
+		return Profile{}, fmt.Errorf("unknown profile %q (available: %s)", name, strings.Join(Names(), ", "))
+	}
+	return p, nil
+}
+
+// Names returns the names of all predefined profiles in sorted order
+func Names() []string {
+	names := make([]string, 0, len(profiles))
+	for name := range profiles {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// IsEmpty reports whether the profile leaves all reference fields unset
+func (p Profile) IsEmpty() bool {
+	return p.StringLen.Max == 0 && p.SliceLen == 0 && p.MapEntries == 0 && !p.BoxInterfaces
+}
+
+// String returns the profile name, or "empty" for the zero value
+func (p Profile) String() string {
+	if p.Name == "" {
+		return "empty"
+	}
+	return p.Name
+}
+
+// alphabet is the set of characters generated strings are built from
+const alphabet = "abcdefghijklmnopqrstuvwxyz0123456789"
+
+// Text returns a string with a length drawn from the profile's distribution.
+// The string is built in a single allocation so no garbage is left behind.
+func (p Profile) Text(rnd *rand.Rand) string {
+	n := p.StringLen.sample(rnd)
+	if n == 0 {
+		return ""
+	}
+
+	var b strings.Builder
+	b.Grow(n)
+	for i := 0; i < n; i++ {
+		b.WriteByte(alphabet[rnd.Intn(len(alphabet))])
+	}
+	return b.String()
+}
+
+// sample draws a length from the distribution
+func (l Lengths) sample(rnd *rand.Rand) int {
+	if l.Max <= l.Min {
+		return l.Max
+	}
+
+	span := l.Max - l.Min
+	if !l.Skewed {
+		return l.Min + rnd.Intn(span+1)
+	}
+
+	// Exponential tail with a mean of a quarter of the range
+	n := l.Min + int(rnd.ExpFloat64()*float64(span)/4)
+	return min(n, l.Max)
+}
+
+// Boxed returns a heap-allocated value to store in an interface field,
+// or nil if the profile does not box interfaces
+func (p Profile) Boxed(rnd *rand.Rand) any {
+	if !p.BoxInterfaces {
+		return nil
+	}
+	// Values below 256 are served from a static table without allocating
+	return int64(256 + rnd.Intn(1<<30))
+}
+
+// Slice returns a slice of the profile's length with elements from gen,
+// or nil for profiles without slices
+func Slice[T any](p Profile, rnd *rand.Rand, gen func(*rand.Rand) T) []T {
+	if p.SliceLen == 0 {
+		return nil
+	}
+
+	s := make([]T, p.SliceLen)
+	for i := range s {
+		s[i] = gen(rnd)
+	}
+	return s
+}
+
+// Map returns a map with the profile's number of entries built from key and
+// value generators, or nil for profiles without maps. Colliding keys are
+// regenerated until the map holds the configured number of entries, so the
+// key generator must be able to produce that many distinct keys.
+func Map[K comparable, V any](p Profile, rnd *rand.Rand, key func(*rand.Rand) K, value func(*rand.Rand) V) map[K]V {
+	if p.MapEntries == 0 {
+		return nil
+	}
+
+	m := make(map[K]V, p.MapEntries)
+	for len(m) < p.MapEntries {
+		m[key(rnd)] = value(rnd)
+	}
+	return m
+}
@@ -0,0 +1,88 @@
+package term
+
+import (
+	"os"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI escape codes used by Style
+const (
+	ansiReset   = "\x1b[0m"
+	ansiBold    = "\x1b[1m"
+	ansiDim     = "\x1b[2m"
+	ansiReverse = "\x1b[7m"
+	ansiRed     = "\x1b[31m"
+	ansiGreen   = "\x1b[32m"
+	ansiCyan    = "\x1b[36m"
+)
+
+// Style colors text with ANSI escape codes. The zero Style leaves text
+// plain, which is what logs and pipes should get.
+type Style struct {
+	Color bool
+}
+
+// NewStyle returns a style that colors output to f if f is a terminal,
+// unless disabled or the NO_COLOR environment variable is set
+func NewStyle(f *os.File, disabled bool) Style {
+	return Style{Color: !disabled && os.Getenv("NO_COLOR") == "" && IsTerminal(f)}
+}
+
+// wrap encloses text in an escape code when color is enabled
+func (s Style) wrap(code, text string) string {
+	if !s.Color || text == "" {
+		return text
+	}
+	return code + text + ansiReset
+}
+
+// Bold emphasizes text such as headings
+func (s Style) Bold(text string) string { return s.wrap(ansiBold, text) }
+
+// Dim de-emphasizes secondary text such as raw byte counts
+func (s Style) Dim(text string) string { return s.wrap(ansiDim, text) }
+
+// Reverse swaps the foreground and background, as for a selected line
+func (s Style) Reverse(text string) string { return s.wrap(ansiReverse, text) }
+
+// Heading marks a section heading
+func (s Style) Heading(text string) string { return s.wrap(ansiBold+ansiCyan, text) }
+
+// Saving marks a memory saving
+func (s Style) Saving(text string) string { return s.wrap(ansiGreen, text) }
+
+// Regression marks a memory regression or a warning
+func (s Style) Regression(text string) string { return s.wrap(ansiRed, text) }
+
+// Signed marks text as a saving if value is positive and as a regression if
+// it is negative
+func (s Style) Signed(value float64, text string) string {
+	switch {
+	case value > 0:
+		return s.Saving(text)
+	case value < 0:
+		return s.Regression(text)
+	}
+	return text
+}
+
+// VisibleWidth returns the number of columns text takes up, ignoring ANSI
+// escape codes
+func VisibleWidth(text string) int {
+	width := 0
+	for len(text) > 0 {
+		if strings.HasPrefix(text, "\x1b[") {
+			end := strings.IndexByte(text, 'm')
+			if end < 0 {
+				break
+			}
+			text = text[end+1:]
+			continue
+		}
+		_, n := utf8.DecodeRuneInString(text)
+		text = text[n:]
+		width++
+	}
+	return width
+}
@@ -0,0 +1,75 @@
+package term
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Table lays out rows of cells in aligned columns. Cells may contain ANSI
+// escape codes, which do not count towards their width.
+type Table struct {
+	header []string
+	rows   [][]string
+	right  map[int]bool
+}
+
+// NewTable returns a table with the given column headers
+func NewTable(header ...string) *Table {
+	return &Table{header: header, right: make(map[int]bool)}
+}
+
+// AlignRight right-aligns the given columns, as for numbers
+func (t *Table) AlignRight(columns ...int) *Table {
+	for _, c := range columns {
+		t.right[c] = true
+	}
+	return t
+}
+
+// Row adds a row of cells
+func (t *Table) Row(cells ...string) {
+	t.rows = append(t.rows, cells)
+}
+
+// Write prints the table to w, each line starting with indent and the
+// header underlined with style
+func (t *Table) Write(w io.Writer, indent string, style Style) {
+	widths := make([]int, len(t.header))
+	for _, row := range append([][]string{t.header}, t.rows...) {
+		for i, cell := range row {
+			if i >= len(widths) {
+				widths = append(widths, 0)
+			}
+			widths[i] = max(widths[i], VisibleWidth(cell))
+		}
+	}
+
+	line := func(cells []string) string {
+		var sb strings.Builder
+		for i, cell := range cells {
+			if i > 0 {
+				sb.WriteString("  ")
+			}
+			pad := strings.Repeat(" ", widths[i]-VisibleWidth(cell))
+			if t.right[i] {
+				sb.WriteString(pad + cell)
+			} else if i < len(cells)-1 {
+				sb.WriteString(cell + pad)
+			} else {
+				sb.WriteString(cell)
+			}
+		}
+		return indent + sb.String()
+	}
+
+	fmt.Fprintln(w, style.Bold(line(t.header)))
+	total := 2 * (len(widths) - 1)
+	for _, width := range widths {
+		total += width
+	}
+	fmt.Fprintln(w, indent+strings.Repeat("-", total))
+	for _, row := range t.rows {
+		fmt.Fprintln(w, line(row))
+	}
+}
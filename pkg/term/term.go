@@ -0,0 +1,54 @@
+// Package term detects the size and capabilities of the terminal and formats
+// colored, aligned output for it without dependencies outside the standard
+// library.
+package term
+
+import (
+	"os"
+	"strconv"
+)
+
+// DefaultWidth is the width assumed when output is not a terminal and
+// COLUMNS is not set, as in CI logs
+const DefaultWidth = 80
+
+// Width returns the width of the terminal f is connected to. If f is not a
+// terminal it falls back to the COLUMNS environment variable and then to
+// DefaultWidth.
+func Width(f *os.File) int {
+	if width, _, ok := size(f.Fd()); ok && width > 0 {
+		return width
+	}
+	if columns, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && columns > 0 {
+		return columns
+	}
+	return DefaultWidth
+}
+
+// Size returns the width and height of the terminal f is connected to
+func Size(f *os.File) (width, height int, ok bool) {
+	return size(f.Fd())
+}
+
+// IsTerminal reports whether f is connected to a terminal
+func IsTerminal(f *os.File) bool {
+	return isTerminal(f.Fd())
+}
+
+// MakeRaw puts the terminal f is connected to into raw mode, where keys are
+// read one at a time without echo, and returns a function that restores the
+// previous mode
+func MakeRaw(f *os.File) (restore func() error, err error) {
+	return makeRaw(f.Fd())
+}
+
+// NotifyResize relays a signal to c whenever the terminal is resized. On
+// platforms without resize signals it does nothing.
+func NotifyResize(c chan<- os.Signal) {
+	notifyResize(c)
+}
+
+// StopResize stops relaying resize signals to c
+func StopResize(c chan<- os.Signal) {
+	stopResize(c)
+}
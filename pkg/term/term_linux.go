@@ -0,0 +1,9 @@
+package term
+
+import "syscall"
+
+// ioctlReadTermios and ioctlWriteTermios get and set the terminal attributes
+const (
+	ioctlReadTermios  = syscall.TCGETS
+	ioctlWriteTermios = syscall.TCSETS
+)
@@ -0,0 +1,27 @@
+//go:build !linux && !darwin
+
+package term
+
+import (
+	"errors"
+	"os"
+)
+
+// Terminal sizes and modes need ioctls this platform does not provide, so
+// output is treated as going to a file
+
+func size(fd uintptr) (width, height int, ok bool) {
+	return 0, 0, false
+}
+
+func isTerminal(fd uintptr) bool {
+	return false
+}
+
+func makeRaw(fd uintptr) (func() error, error) {
+	return nil, errors.New("raw terminal mode is not supported on this platform")
+}
+
+func notifyResize(c chan<- os.Signal) {}
+
+func stopResize(c chan<- os.Signal) {}
@@ -0,0 +1,25 @@
+// Toggle byte values visibility
+document.addEventListener('DOMContentLoaded', function() {
+    const bytesValues = document.querySelectorAll('.bytes-value');
+    let bytesVisible = false;
+
+    // Add button to toggle byte values
+    const toggleBtn = document.createElement('button');
+    toggleBtn.innerText = 'Show Raw Bytes';
+    toggleBtn.style = 'margin: 20px 0; padding: 8px 16px;';
+    toggleBtn.onclick = function() {
+        bytesVisible = !bytesVisible;
+        bytesValues.forEach(el => {
+            el.style.display = bytesVisible ? 'inline' : 'none';
+        });
+        this.innerText = bytesVisible ? 'Hide Raw Bytes' : 'Show Raw Bytes';
+    };
+
+    // Initially hide byte values
+    bytesValues.forEach(el => {
+        el.style.display = 'none';
+    });
+
+    // Add button to page
+    document.querySelector('.container').insertBefore(toggleBtn, document.querySelector('h2'));
+});
@@ -0,0 +1,147 @@
+package visualizer
+
+import (
+	"fmt"
+	"io"
+	"mem-tests/pkg/layout"
+	"mem-tests/pkg/memory"
+	"os"
+	"strings"
+)
+
+const (
+	// wordSize is the number of bytes between word boundary marks
+	wordSize = 8
+
+	// paddingCell marks a byte of padding in a byte map
+	paddingCell = '░'
+
+	// wordMark separates the words of a byte map
+	wordMark = "|"
+
+	// cellLetters are assigned to fields in order of first appearance
+	cellLetters = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+)
+
+// namedLayout is a struct layout with the label it is drawn under
+type namedLayout struct {
+	label  string
+	layout layout.Layout
+}
+
+// layoutVariants returns the optimized and unoptimized layouts stored in a
+// result's stats, if the test attached them, labelled with the names of the
+// compared variants
+func layoutVariants(stats map[string]interface{}, names memory.Variants) []namedLayout {
+	var variants []namedLayout
+	if l, ok := stats["OptimizedLayout"].(layout.Layout); ok {
+		variants = append(variants, namedLayout{names.Optimized, l})
+	}
+	if l, ok := stats["UnoptimizedLayout"].(layout.Layout); ok {
+		variants = append(variants, namedLayout{names.Unoptimized, l})
+	}
+	return variants
+}
+
+// printLayouts draws the layouts stored in a result's stats, if any
+func printLayouts(stats map[string]interface{}, names memory.Variants, width int) {
+	if variants := layoutVariants(stats, names); len(variants) > 0 {
+		writeByteMaps(os.Stdout, variants, width)
+	}
+}
+
+// writeByteMaps draws each layout as a row of byte cells lettered by field,
+// with padding as shaded cells and a mark at every word boundary. Long
+// structs wrap, and the rows of every variant covering the same offsets are
+// stacked so matching bytes line up.
+func writeByteMaps(w io.Writer, variants []namedLayout, width int) {
+	// The same field gets the same letter in every variant
+	letters := make(map[string]rune)
+	var legend []string
+	for _, v := range variants {
+		for _, f := range v.layout.Fields {
+			if _, ok := letters[f.Name]; ok {
+				continue
+			}
+			letter := '#'
+			if n := len(letters); n < len(cellLetters) {
+				letter = rune(cellLetters[n])
+			}
+			letters[f.Name] = letter
+			legend = append(legend, fmt.Sprintf("%c=%s (%s, %d)", letter, f.Name, f.Type, f.Size))
+		}
+	}
+
+	labelWidth := 0
+	var maxSize uintptr
+	cells := make([][]rune, len(variants))
+	for i, v := range variants {
+		labelWidth = max(labelWidth, len(v.label))
+		maxSize = max(maxSize, v.layout.Size)
+		cells[i] = byteCells(v.layout, letters)
+	}
+
+	// Each row starts with the label and a 6 digit offset
+	prefixWidth := labelWidth + 8
+	wordsPerRow := max(1, (width-prefixWidth-len(wordMark))/(wordSize+len(wordMark)))
+	rowBytes := uintptr(wordsPerRow * wordSize)
+
+	fmt.Fprintf(w, "Byte layout (%c = padding, %s = %d-byte word boundary):\n", paddingCell, wordMark, wordSize)
+	for start := uintptr(0); start < maxSize; start += rowBytes {
+		for i, v := range variants {
+			if start >= v.layout.Size {
+				continue
+			}
+			end := min(start+rowBytes, v.layout.Size)
+			fmt.Fprintf(w, "%-*s %06d %s\n", labelWidth, v.label, start, formatCells(cells[i][start:end]))
+		}
+		if len(variants) > 1 && start+rowBytes < maxSize {
+			fmt.Fprintln(w)
+		}
+	}
+
+	for _, v := range variants {
+		fmt.Fprintf(w, "%s: %d bytes, %d bytes of padding\n", v.label, v.layout.Size, v.layout.Padding)
+	}
+	writeLegend(w, legend, width)
+}
+
+// byteCells assigns every byte of a layout the letter of the field covering it
+func byteCells(l layout.Layout, letters map[string]rune) []rune {
+	cells := make([]rune, l.Size)
+	for i := range cells {
+		cells[i] = paddingCell
+	}
+	for _, f := range l.Fields {
+		for b := f.Offset; b < f.Offset+f.Size && b < l.Size; b++ {
+			cells[b] = letters[f.Name]
+		}
+	}
+	return cells
+}
+
+// formatCells joins byte cells with a mark at the start of every word
+func formatCells(cells []rune) string {
+	var sb strings.Builder
+	for i, c := range cells {
+		if i%wordSize == 0 {
+			sb.WriteString(wordMark)
+		}
+		sb.WriteRune(c)
+	}
+	sb.WriteString(wordMark)
+	return sb.String()
+}
+
+// writeLegend writes the legend entries, as many per line as fit the width
+func writeLegend(w io.Writer, entries []string, width int) {
+	line := "Legend:"
+	for _, entry := range entries {
+		if len(line)+1+len(entry) > width {
+			fmt.Fprintln(w, line)
+			line = "       "
+		}
+		line += " " + entry
+	}
+	fmt.Fprintln(w, line)
+}
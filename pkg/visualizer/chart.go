@@ -0,0 +1,260 @@
+package visualizer
+
+import (
+	"fmt"
+	"math"
+	"mem-tests/pkg/memory"
+	"strings"
+)
+
+const (
+	// chartWidth and chartHeight are the size of a bar chart's view box
+	chartWidth  = 1000
+	chartHeight = 400
+
+	// chartLeft, chartRight, chartTop and chartBottom are the margins around
+	// the plot area, which hold the axis labels and the legend
+	chartLeft   = 90
+	chartRight  = 20
+	chartTop    = 40
+	chartBottom = 40
+
+	// chartTicks is the approximate number of gridlines on the value axis
+	chartTicks = 5
+
+	// lineChartWidth, lineChartHeight and lineChartTicks are the size and
+	// number of gridlines of a trend line chart, which shares the margins of
+	// the bar charts
+	lineChartWidth  = 500
+	lineChartHeight = 220
+	lineChartTicks  = 4
+)
+
+// chartGroup is one label on a bar chart with its optimized and unoptimized values
+type chartGroup struct {
+	label       string
+	optimized   uint64
+	unoptimized uint64
+}
+
+// barChart is the data the bar-chart template draws: a grouped bar chart
+// comparing the optimized and unoptimized memory of each group
+type barChart struct {
+	Width, Height int
+
+	// Left, Right and Bottom are the edges of the plot area, and LabelX is
+	// where the value labels end
+	Left, Right, Bottom, LabelX int
+
+	Legend    []chartLegend
+	Gridlines []chartGridline
+	Bars      []chartBar
+	Labels    []chartLabel
+}
+
+// chartLegend is one entry of a chart legend, a swatch at X and its label at
+// TextX
+type chartLegend struct {
+	Class    string
+	X, TextX int
+	Label    string
+}
+
+// chartGridline is a horizontal gridline with its value label, which sits
+// at LabelY
+type chartGridline struct {
+	Y, LabelY float64
+	Label     string
+}
+
+// chartBar is one bar with its tooltip
+type chartBar struct {
+	Class               string
+	X, Y, Width, Height float64
+	Title               string
+}
+
+// chartLabel is a label on the time or group axis
+type chartLabel struct {
+	X      float64
+	Y      int
+	Anchor string
+	Text   string
+}
+
+// newBarChart lays out a grouped bar chart comparing the optimized and
+// unoptimized memory of each group. The legend and tooltips name the two
+// sides after variants.
+func newBarChart(groups []chartGroup, variants memory.Variants) barChart {
+	var maxVal uint64
+	for _, g := range groups {
+		maxVal = max(maxVal, g.optimized, g.unoptimized)
+	}
+	step := tickStep(maxVal)
+	top := step * uint64(math.Ceil(float64(max(maxVal, 1))/float64(step)))
+
+	plotWidth := float64(chartWidth - chartLeft - chartRight)
+	plotHeight := float64(chartHeight - chartTop - chartBottom)
+	y := func(v uint64) float64 {
+		return chartTop + plotHeight*(1-float64(v)/float64(top))
+	}
+
+	chart := barChart{
+		Width:  chartWidth,
+		Height: chartHeight,
+		Left:   chartLeft,
+		Right:  chartWidth - chartRight,
+		Bottom: chartHeight - chartBottom,
+		LabelX: chartLeft - 8,
+		Legend: []chartLegend{
+			{Class: "bar-optimized", X: chartLeft, TextX: chartLeft + 20, Label: variants.Optimized},
+			{Class: "bar-unoptimized", X: chartLeft + 110, TextX: chartLeft + 130, Label: variants.Unoptimized},
+		},
+	}
+	for v := uint64(0); v <= top; v += step {
+		chart.Gridlines = append(chart.Gridlines, chartGridline{Y: y(v), LabelY: y(v) + 4, Label: memory.FormatBytes(v)})
+	}
+
+	groupWidth := plotWidth / float64(max(len(groups), 1))
+	barWidth := groupWidth * 0.35
+	for i, g := range groups {
+		x := chartLeft + groupWidth*float64(i) + groupWidth*0.15
+		for j, bar := range []struct {
+			class string
+			name  string
+			value uint64
+		}{
+			{"bar-optimized", variants.Optimized, g.optimized},
+			{"bar-unoptimized", variants.Unoptimized, g.unoptimized},
+		} {
+			chart.Bars = append(chart.Bars, chartBar{
+				Class:  bar.class,
+				X:      x + barWidth*float64(j),
+				Y:      y(bar.value),
+				Width:  barWidth,
+				Height: y(0) - y(bar.value),
+				Title:  fmt.Sprintf("%s %s: %s (%d bytes)", g.label, bar.name, memory.FormatBytes(bar.value), bar.value),
+			})
+		}
+		chart.Labels = append(chart.Labels, chartLabel{
+			X: x + barWidth, Y: chartHeight - chartBottom + 20, Anchor: "middle", Text: g.label,
+		})
+	}
+
+	return chart
+}
+
+// tickStep returns a round step between gridlines for values up to maxVal.
+// Steps are 1, 2 or 5 times a power of ten in the largest binary unit that
+// fits, so the labels read as whole KB, MB or GB.
+func tickStep(maxVal uint64) uint64 {
+	unit := uint64(1)
+	for maxVal/unit >= 1024 {
+		unit *= 1024
+	}
+
+	step := niceStep(float64(maxVal) / float64(unit) / chartTicks)
+	return max(1, uint64(step*float64(unit)))
+}
+
+// niceStep rounds raw up to 1, 2 or 5 times a power of ten
+func niceStep(raw float64) float64 {
+	if raw <= 0 {
+		return 1
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, m := range []float64{1, 2, 5} {
+		if raw <= m*magnitude {
+			return m * magnitude
+		}
+	}
+	return magnitude * 10
+}
+
+// lineChart is the data the line-chart template draws: the values of a
+// series as a small line chart, one point per label
+type lineChart struct {
+	Width, Height int
+
+	// Left and Right are the edges of the plot area, and LabelX is where the
+	// value labels end
+	Left, Right, LabelX int
+
+	Title     string
+	Gridlines []chartGridline
+
+	// Line is the polyline through the points, in SVG points syntax
+	Line   string
+	Points []chartPoint
+	Labels []chartLabel
+}
+
+// chartPoint is one point of a line chart with its tooltip
+type chartPoint struct {
+	X, Y  float64
+	Jump  bool
+	Title string
+}
+
+// newLineChart lays out the values of a series as a small line chart, one
+// point per label, with a tooltip on every point. Points flagged in jumps are
+// highlighted.
+func newLineChart(title string, labels []string, values []float64, jumps []bool, format func(float64) string) lineChart {
+	lo, hi := 0.0, 0.0
+	for _, v := range values {
+		lo, hi = min(lo, v), max(hi, v)
+	}
+	step := niceStep((hi - lo) / lineChartTicks)
+	lo = step * math.Floor(lo/step)
+	hi = step * math.Ceil(hi/step)
+	if hi == lo {
+		hi = lo + step
+	}
+
+	plotWidth := float64(lineChartWidth - chartLeft - chartRight)
+	plotHeight := float64(lineChartHeight - chartTop - chartBottom)
+	x := func(i int) float64 {
+		if len(values) < 2 {
+			return chartLeft + plotWidth/2
+		}
+		return chartLeft + plotWidth*float64(i)/float64(len(values)-1)
+	}
+	y := func(v float64) float64 {
+		return chartTop + plotHeight*(hi-v)/(hi-lo)
+	}
+
+	chart := lineChart{
+		Width:  lineChartWidth,
+		Height: lineChartHeight,
+		Left:   chartLeft,
+		Right:  lineChartWidth - chartRight,
+		LabelX: chartLeft - 8,
+		Title:  title,
+	}
+	for v := lo; v <= hi+step/2; v += step {
+		chart.Gridlines = append(chart.Gridlines, chartGridline{Y: y(v), LabelY: y(v) + 4, Label: format(v)})
+	}
+
+	points := make([]string, len(values))
+	for i, v := range values {
+		points[i] = fmt.Sprintf("%.1f,%.1f", x(i), y(v))
+		chart.Points = append(chart.Points, chartPoint{
+			X: x(i), Y: y(v), Jump: jumps[i], Title: fmt.Sprintf("%s: %s", labels[i], format(v)),
+		})
+	}
+	chart.Line = strings.Join(points, " ")
+
+	// Label the first and last run on the time axis
+	if len(labels) > 0 {
+		chart.Labels = append(chart.Labels, chartLabel{
+			X: x(0), Y: lineChartHeight - chartBottom + 20, Anchor: "start", Text: labels[0],
+		})
+	}
+	if len(labels) > 1 {
+		chart.Labels = append(chart.Labels, chartLabel{
+			X: x(len(labels) - 1), Y: lineChartHeight - chartBottom + 20, Anchor: "end", Text: labels[len(labels)-1],
+		})
+	}
+
+	return chart
+}
@@ -0,0 +1,217 @@
+package visualizer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Retention selects the history runs to keep when old history is pruned.
+// A run is kept if any rule keeps it. The zero Retention keeps every run.
+type Retention struct {
+	// Last keeps the most recent runs
+	Last int
+
+	// Daily, Weekly and Monthly keep the most recent run of each of that
+	// many days, ISO weeks and months that have runs
+	Daily   int
+	Weekly  int
+	Monthly int
+}
+
+// ParseRetention parses a retention spec of comma separated rule=count
+// pairs, for example "last=10" or "daily=7,weekly=4,monthly=12". An empty
+// spec keeps every run.
+func ParseRetention(spec string) (Retention, error) {
+	var r Retention
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		rule, countStr, ok := strings.Cut(part, "=")
+		if !ok {
+			return r, fmt.Errorf("retention rule '%s' must be rule=count", part)
+		}
+		count, err := strconv.Atoi(strings.TrimSpace(countStr))
+		if err != nil || count < 1 {
+			return r, fmt.Errorf("retention count '%s' must be a positive integer", countStr)
+		}
+
+		switch strings.TrimSpace(rule) {
+		case "last":
+			r.Last = count
+		case "daily":
+			r.Daily = count
+		case "weekly":
+			r.Weekly = count
+		case "monthly":
+			r.Monthly = count
+		default:
+			return r, fmt.Errorf("unknown retention rule '%s' (want last, daily, weekly or monthly)", rule)
+		}
+	}
+	return r, nil
+}
+
+// IsZero reports whether the retention keeps every run
+func (r Retention) IsZero() bool {
+	return r == Retention{}
+}
+
+// String formats the retention as a spec ParseRetention accepts
+func (r Retention) String() string {
+	var parts []string
+	for _, rule := range []struct {
+		name  string
+		count int
+	}{
+		{"last", r.Last}, {"daily", r.Daily}, {"weekly", r.Weekly}, {"monthly", r.Monthly},
+	} {
+		if rule.count > 0 {
+			parts = append(parts, fmt.Sprintf("%s=%d", rule.name, rule.count))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+// Keep returns which of the run times to keep. Each thinning rule walks the
+// runs from newest to oldest and keeps the first run of every new period
+// until it has kept its count.
+func (r Retention) Keep(times []time.Time) []bool {
+	keep := make([]bool, len(times))
+	if r.IsZero() {
+		for i := range keep {
+			keep[i] = true
+		}
+		return keep
+	}
+
+	order := make([]int, len(times))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool { return times[order[a]].After(times[order[b]]) })
+
+	for n, i := range order {
+		if n < r.Last {
+			keep[i] = true
+		}
+	}
+
+	for _, rule := range []struct {
+		count  int
+		period func(time.Time) string
+	}{
+		{r.Daily, func(t time.Time) string { return t.Format("2006-01-02") }},
+		{r.Weekly, func(t time.Time) string {
+			year, week := t.ISOWeek()
+			return fmt.Sprintf("%d-W%02d", year, week)
+		}},
+		{r.Monthly, func(t time.Time) string { return t.Format("2006-01") }},
+	} {
+		seen := make(map[string]bool)
+		for _, i := range order {
+			if len(seen) >= rule.count {
+				break
+			}
+			period := rule.period(times[i])
+			if !seen[period] {
+				seen[period] = true
+				keep[i] = true
+			}
+		}
+	}
+
+	return keep
+}
+
+// historyRun is one run found in the history, with the file that records it
+type historyRun struct {
+	dir  string
+	path string
+	time time.Time
+}
+
+// pruneHistory removes the runs in the history of baseDir that the
+// retention does not keep, and the history directories left without a run.
+// Directories from before structured results were stored count as a single
+// run at the start of their date. It returns the paths it removed.
+func pruneHistory(baseDir string, retention Retention) ([]string, error) {
+	if retention.IsZero() {
+		return nil, nil
+	}
+
+	dirs, err := filepath.Glob(filepath.Join(baseDir, "history", "*"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read history: %w", err)
+	}
+
+	var runs []historyRun
+	for _, dir := range dirs {
+		date, err := time.ParseInLocation("2006-01-02", filepath.Base(dir), time.Local)
+		if err != nil {
+			// Not a history directory
+			continue
+		}
+
+		files, err := filepath.Glob(filepath.Join(dir, resultsPattern))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read history: %w", err)
+		}
+		if len(files) == 0 {
+			runs = append(runs, historyRun{dir: dir, path: dir, time: date})
+			continue
+		}
+		for _, file := range files {
+			stamp := strings.TrimSuffix(strings.TrimPrefix(filepath.Base(file), "results-"), ".json")
+			runTime, err := time.ParseInLocation("2006-01-02 150405", filepath.Base(dir)+" "+stamp, time.Local)
+			if err != nil {
+				runTime = date
+			}
+			runs = append(runs, historyRun{dir: dir, path: file, time: runTime})
+		}
+	}
+
+	times := make([]time.Time, len(runs))
+	for i, run := range runs {
+		times[i] = run.time
+	}
+	keep := retention.Keep(times)
+
+	keptDirs := make(map[string]bool)
+	for i, run := range runs {
+		if keep[i] {
+			keptDirs[run.dir] = true
+		}
+	}
+
+	var removed []string
+	removedDirs := make(map[string]bool)
+	for i, run := range runs {
+		if keep[i] || removedDirs[run.dir] {
+			continue
+		}
+
+		// A directory without a kept run goes as a whole, along with its pages
+		if !keptDirs[run.dir] {
+			if err := os.RemoveAll(run.dir); err != nil {
+				return removed, fmt.Errorf("failed to remove history directory: %w", err)
+			}
+			removedDirs[run.dir] = true
+			removed = append(removed, run.dir)
+			continue
+		}
+
+		if err := os.Remove(run.path); err != nil {
+			return removed, fmt.Errorf("failed to remove stored results: %w", err)
+		}
+		removed = append(removed, run.path)
+	}
+	return removed, nil
+}
@@ -0,0 +1,77 @@
+package visualizer
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"mem-tests/pkg/memory"
+	"os"
+	"path/filepath"
+)
+
+// summaryFile is the file in the GitHub Pages directory that records the
+// headline saving of every exported test, keyed by page file name
+const summaryFile = "summary.json"
+
+// testSummary is the headline result of a test shown in the index
+type testSummary struct {
+	Name          string  `json:"name"`
+	Saved         uint64  `json:"saved"`
+	Unoptimized   uint64  `json:"unoptimized"`
+	SavingPercent float64 `json:"savingPercent"`
+	Seed          int64   `json:"seed"`
+	Updated       string  `json:"updated"`
+}
+
+// summarize returns the headline saving of a result. For multi-type tests
+// this is the total saving relative to the summed unoptimized memory.
+func summarize(r memory.TestResult, updated string) testSummary {
+	s := testSummary{Name: r.Name, Seed: r.Seed, Updated: updated}
+
+	if typeResults, ok := r.OtherStats["TypeResults"].(map[string]map[string]interface{}); ok {
+		for _, typeData := range typeResults {
+			unoptMem, _ := typeData["UnoptimizedMemory"].(uint64)
+			s.Unoptimized += unoptMem
+		}
+		s.Saved, _ = r.OtherStats["TotalSaving"].(uint64)
+		if s.Unoptimized > 0 {
+			s.SavingPercent = float64(s.Saved) / float64(s.Unoptimized) * 100
+		}
+		return s
+	}
+
+	s.Unoptimized, _ = r.OtherStats["UnoptimizedMemory"].(uint64)
+	s.Saved = r.MemoryUsed
+	s.SavingPercent, _ = r.OtherStats["MemorySavingPercent"].(float64)
+	return s
+}
+
+// loadSummaries reads the summaries recorded in baseDir. A missing file
+// yields an empty map, since pages exported before summaries were recorded
+// are listed without one.
+func loadSummaries(baseDir string) (map[string]testSummary, error) {
+	summaries := make(map[string]testSummary)
+	data, err := os.ReadFile(filepath.Join(baseDir, summaryFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return summaries, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read summaries: %w", err)
+	}
+	if err := json.Unmarshal(data, &summaries); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", summaryFile, err)
+	}
+	return summaries, nil
+}
+
+// saveSummaries writes the summaries to baseDir
+func saveSummaries(baseDir string, summaries map[string]testSummary) error {
+	data, err := json.MarshalIndent(summaries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(baseDir, summaryFile), append(data, '\n'), 0644); err != nil {
+		return fmt.Errorf("failed to write summaries: %w", err)
+	}
+	return nil
+}
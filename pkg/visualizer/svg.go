@@ -0,0 +1,217 @@
+package visualizer
+
+import (
+	"fmt"
+	"mem-tests/pkg/layout"
+	"mem-tests/pkg/memory"
+)
+
+const (
+	// svgByteHeight is the height of one byte of a struct in a layout diagram
+	svgByteHeight = 3
+
+	// svgColumnWidth is the width of the column a struct is drawn in
+	svgColumnWidth = 280
+
+	// svgColumnGap is the space between columns, where the connecting lines run
+	svgColumnGap = 140
+
+	// svgMargin is the space around the columns, which holds the offset labels
+	svgMargin = 48
+
+	// svgHeaderHeight is the space above the columns for their titles
+	svgHeaderHeight = 48
+
+	// svgLabelHeight is the smallest box height that fits a field label
+	svgLabelHeight = 12
+)
+
+// layoutSVG is the data the layout-svg template draws: each layout as a
+// column of field boxes scaled by size, with lines joining the boxes of the
+// same field in neighbouring columns
+type layoutSVG struct {
+	Width, Height int
+
+	// Hatch is the id of the pattern padding is filled with, unique in the page
+	Hatch string
+
+	Links   []layoutLink
+	Columns []layoutColumn
+}
+
+// layoutLink joins the boxes of one field in neighbouring columns
+type layoutLink struct {
+	Class          string
+	X1, Y1, X2, Y2 int
+	Stroke         string
+
+	// Name is the field and From and To its offsets in the two columns
+	Name     string
+	From, To uintptr
+}
+
+// layoutColumn is one layout of a diagram
+type layoutColumn struct {
+	X, TitleY, SizeY int
+
+	Label, Name   string
+	Size, Padding uintptr
+
+	Fields []layoutBox
+	Words  []layoutWord
+
+	// X1 and X2 are the ends of the word boundaries, and LabelX and Anchor
+	// place their offset labels on the outer side of the column
+	X1, X2, LabelX int
+	Anchor         string
+}
+
+// layoutBox is the box of one field with the padding that follows it. The
+// box is drawn if Size is not zero and the padding if Padding is not zero.
+type layoutBox struct {
+	Name, Type          string
+	Offset, Size, Align uintptr
+	Y, Width, Height    int
+	Fill                string
+
+	// Labelled is set when the box is tall enough for the label at TextX, TextY
+	Labelled     bool
+	TextX, TextY int
+
+	PaddingOffset, Padding  uintptr
+	PaddingY, PaddingHeight int
+}
+
+// layoutWord is a word boundary of a column
+type layoutWord struct {
+	Offset    uintptr
+	Y, LabelY int
+}
+
+// newLayoutDiagram draws the optimized and unoptimized layouts stored in a
+// result's stats, if the test attached them, labelled with the variant names
+func newLayoutDiagram(id string, title string, stats map[string]interface{}, names memory.Variants) (layoutDiagram, bool) {
+	variants := layoutVariants(stats, names)
+	if len(variants) == 0 {
+		return layoutDiagram{}, false
+	}
+
+	d := layoutDiagram{Title: title, SVG: newLayoutSVG(id, variants)}
+	for _, v := range variants {
+		d.Variants = append(d.Variants, layoutVariant{Label: v.label, Layout: v.layout})
+	}
+	return d, true
+}
+
+// newLayoutSVG lays out each layout as a column of field boxes scaled by
+// size, with padding hatched, a tooltip on every box and lines joining the
+// boxes of the same field in neighbouring columns
+func newLayoutSVG(id string, variants []namedLayout) layoutSVG {
+	var maxSize uintptr
+	for _, v := range variants {
+		maxSize = max(maxSize, v.layout.Size)
+	}
+	svg := layoutSVG{
+		Width:  2*svgMargin + len(variants)*svgColumnWidth + (len(variants)-1)*svgColumnGap,
+		Height: svgHeaderHeight + int(maxSize)*svgByteHeight + svgMargin/2,
+		Hatch:  id + "-hatch",
+	}
+
+	// Every field keeps its hue across the variants
+	hues := make(map[string]int)
+	for _, v := range variants {
+		for _, f := range v.layout.Fields {
+			if _, ok := hues[f.Name]; !ok {
+				hues[f.Name] = len(hues) * 47 % 360
+			}
+		}
+	}
+
+	columnX := func(i int) int {
+		return svgMargin + i*(svgColumnWidth+svgColumnGap)
+	}
+	byteY := func(offset uintptr) int {
+		return svgHeaderHeight + int(offset)*svgByteHeight
+	}
+
+	// Links are drawn first so the boxes are drawn over their ends
+	for i := 0; i+1 < len(variants); i++ {
+		right := make(map[string]layout.Field)
+		for _, f := range variants[i+1].layout.Fields {
+			right[f.Name] = f
+		}
+		for _, f := range variants[i].layout.Fields {
+			g, ok := right[f.Name]
+			if !ok {
+				continue
+			}
+			class := "layout-link"
+			if f.Offset != g.Offset {
+				class += " moved"
+			}
+			svg.Links = append(svg.Links, layoutLink{
+				Class:  class,
+				X1:     columnX(i) + svgColumnWidth,
+				Y1:     byteY(f.Offset) + int(f.Size)*svgByteHeight/2,
+				X2:     columnX(i + 1),
+				Y2:     byteY(g.Offset) + int(g.Size)*svgByteHeight/2,
+				Stroke: fmt.Sprintf("hsl(%d, 55%%, 45%%)", hues[f.Name]),
+				Name:   f.Name,
+				From:   f.Offset,
+				To:     g.Offset,
+			})
+		}
+	}
+
+	for i, v := range variants {
+		x := columnX(i)
+		column := layoutColumn{
+			X:       x,
+			TitleY:  svgHeaderHeight - 28,
+			SizeY:   svgHeaderHeight - 12,
+			Label:   v.label,
+			Name:    v.layout.Name(),
+			Size:    v.layout.Size,
+			Padding: v.layout.Padding,
+			X1:      x - 3,
+			X2:      x + svgColumnWidth + 3,
+			LabelX:  x - 6,
+			Anchor:  "end",
+		}
+
+		for _, f := range v.layout.Fields {
+			boxHeight := int(f.Size) * svgByteHeight
+			padOffset := f.Offset + f.Size
+			column.Fields = append(column.Fields, layoutBox{
+				Name:          f.Name,
+				Type:          f.Type.String(),
+				Offset:        f.Offset,
+				Size:          f.Size,
+				Align:         f.Align,
+				Y:             byteY(f.Offset),
+				Width:         svgColumnWidth,
+				Height:        boxHeight,
+				Fill:          fmt.Sprintf("hsl(%d, 65%%, 78%%)", hues[f.Name]),
+				Labelled:      boxHeight >= svgLabelHeight,
+				TextX:         x + 6,
+				TextY:         byteY(f.Offset) + boxHeight/2 + 4,
+				PaddingOffset: padOffset,
+				Padding:       f.Padding,
+				PaddingY:      byteY(padOffset),
+				PaddingHeight: int(f.Padding) * svgByteHeight,
+			})
+		}
+
+		// Word boundaries go over the boxes, labelled on the outer side of the column
+		if i == len(variants)-1 && i > 0 {
+			column.LabelX, column.Anchor = x+svgColumnWidth+6, "start"
+		}
+		for offset := uintptr(0); offset <= v.layout.Size; offset += wordSize {
+			column.Words = append(column.Words, layoutWord{Offset: offset, Y: byteY(offset), LabelY: byteY(offset) + 3})
+		}
+
+		svg.Columns = append(svg.Columns, column)
+	}
+
+	return svg
+}
@@ -0,0 +1,203 @@
+package visualizer
+
+import (
+	"embed"
+	"fmt"
+	"html/template"
+	"mem-tests/pkg/escape"
+	"mem-tests/pkg/layout"
+	"mem-tests/pkg/memory"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// defaultTemplates are the templates the HTML pages are rendered with unless
+// a template directory overrides them
+//
+//go:embed templates/*.html
+var defaultTemplates embed.FS
+
+// Template names of the pages. Shared parts such as the footer are defined
+// in partials.html and can be overridden on their own.
+const (
+	reportTemplate       = "report.html"
+	indexTemplate        = "index.html"
+	historyIndexTemplate = "history_index.html"
+	trendsTemplate       = "trends.html"
+)
+
+// templateFuncs are the functions available to the templates
+var templateFuncs = template.FuncMap{
+	"formatBytes": memory.FormatBytes,
+}
+
+// loadTemplates parses the default templates and then the *.html files in
+// dir, if set. A file in dir replaces the default template of the same name,
+// and any {{define}} block in it replaces the block of the same name, so a
+// directory only needs to hold the parts it changes.
+func loadTemplates(dir string) (*template.Template, error) {
+	tmpl, err := template.New("").Funcs(templateFuncs).ParseFS(defaultTemplates, "templates/*.html")
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse default templates: %w", err)
+	}
+	if dir == "" {
+		return tmpl, nil
+	}
+
+	files, err := filepath.Glob(filepath.Join(dir, "*.html"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read template directory: %w", err)
+	}
+	if len(files) == 0 {
+		return nil, fmt.Errorf("no *.html templates in %s", dir)
+	}
+	if tmpl, err = tmpl.ParseFiles(files...); err != nil {
+		return nil, fmt.Errorf("failed to parse templates in %s: %w", dir, err)
+	}
+	return tmpl, nil
+}
+
+// reportPage is the data the report template is rendered with
+type reportPage struct {
+	Title     string
+	Generated string
+
+	// IndexLink is the path of the GitHub Pages index relative to the page,
+	// or empty if the results are not exported
+	IndexLink string
+
+	Styles template.CSS
+	Script template.JS
+	Tests  []testSection
+
+	// Breakdown adds a field by field table under each layout diagram
+	Breakdown bool
+}
+
+// testSection is the part of the report showing one test result
+type testSection struct {
+	Name  string
+	Seed  int64
+	Chart barChart
+	Rows  []resultRow
+
+	// Variants names the memory columns of the result table
+	Variants memory.Variants
+
+	// Total is the summed saving of a multi-type test, or nil
+	Total *uint64
+
+	// EscapeAnalyzed is set when the test was run with -escape, and
+	// MovedToHeap holds the variables the compiler moved to the heap
+	EscapeAnalyzed bool
+	MovedToHeap    []escape.Decision
+
+	Layouts []layoutDiagram
+}
+
+// resultRow is one row of a test's result table
+type resultRow struct {
+	Name          string
+	Optimized     uint64
+	Unoptimized   uint64
+	Saved         uint64
+	SavingPercent float64
+}
+
+// layoutDiagram is an SVG drawing of the layouts of one compared struct
+type layoutDiagram struct {
+	Title    string
+	SVG      layoutSVG
+	Variants []layoutVariant
+}
+
+// layoutVariant is one of the layouts drawn in a diagram
+type layoutVariant struct {
+	Label  string
+	Layout layout.Layout
+}
+
+// indexPage is the data the index and history index templates are rendered with
+type indexPage struct {
+	Title   string
+	Date    string
+	Tests   []pageLink
+	History []pageLink
+}
+
+// pageLink is one entry in an index page
+type pageLink struct {
+	Href  string
+	Title string
+	Date  string
+
+	// Summary is the headline result of the linked test page, if recorded
+	Summary *testSummary
+}
+
+// newReportPage collects what the report shows for the results
+func newReportPage(title string, results []memory.TestResult, indexLink string) reportPage {
+	page := reportPage{
+		Title:     title,
+		Generated: time.Now().Format("2006-01-02 15:04:05"),
+		IndexLink: indexLink,
+		Styles:    template.CSS(reportCSS),
+		Script:    template.JS(reportJS),
+	}
+	for i, r := range results {
+		page.Tests = append(page.Tests, newTestSection(i, r))
+	}
+	return page
+}
+
+// newTestSection collects what the report shows for one test result. The
+// i-th result's layout diagrams get ids that are unique in the page.
+func newTestSection(i int, r memory.TestResult) testSection {
+	section := testSection{
+		Name:     r.Name,
+		Seed:     r.Seed,
+		Chart:    newBarChart(chartGroups(r), r.Variants()),
+		Variants: r.Variants(),
+	}
+
+	if typeResults, ok := r.OtherStats["TypeResults"].(map[string]map[string]interface{}); ok {
+		typeNames := make([]string, 0, len(typeResults))
+		for typeName := range typeResults {
+			typeNames = append(typeNames, typeName)
+		}
+		sort.Strings(typeNames)
+
+		for j, typeName := range typeNames {
+			typeData := typeResults[typeName]
+			optMem, _ := typeData["OptimizedMemory"].(uint64)
+			unoptMem, _ := typeData["UnoptimizedMemory"].(uint64)
+			saved, _ := typeData["MemorySaved"].(uint64)
+			savingPct, _ := typeData["SavingPercent"].(float64)
+			section.Rows = append(section.Rows, resultRow{typeName, optMem, unoptMem, saved, savingPct})
+
+			if d, ok := newLayoutDiagram(fmt.Sprintf("layout%d-%d", i, j), typeName, typeData, section.Variants); ok {
+				section.Layouts = append(section.Layouts, d)
+			}
+		}
+		if totalSaving, ok := r.OtherStats["TotalSaving"].(uint64); ok {
+			section.Total = &totalSaving
+		}
+	} else {
+		optMem, _ := r.OtherStats["OptimizedMemory"].(uint64)
+		unoptMem, _ := r.OtherStats["UnoptimizedMemory"].(uint64)
+		savingPct, _ := r.OtherStats["MemorySavingPercent"].(float64)
+		section.Rows = append(section.Rows, resultRow{"Standard", optMem, unoptMem, r.MemoryUsed, savingPct})
+
+		if d, ok := newLayoutDiagram(fmt.Sprintf("layout%d", i), r.Name, r.OtherStats, section.Variants); ok {
+			section.Layouts = append(section.Layouts, d)
+		}
+	}
+
+	if moved, ok := r.OtherStats["MovedToHeap"].([]escape.Decision); ok {
+		section.EscapeAnalyzed = true
+		section.MovedToHeap = moved
+	}
+
+	return section
+}
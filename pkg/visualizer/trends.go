@@ -0,0 +1,326 @@
+package visualizer
+
+import (
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"math"
+	"mem-tests/pkg/memory"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	// resultsPattern matches the structured results saved in a history
+	// directory by every export
+	resultsPattern = "results-*.json"
+
+	// trendsFile is the trends page in the GitHub Pages directory
+	trendsFile = "trends.html"
+
+	// trendJumpPoints is the change in saving percent between consecutive
+	// runs, in percentage points, that is flagged as a jump
+	trendJumpPoints = 5.0
+
+	// trendJumpRatio is the relative change in bytes saved per object
+	// between consecutive runs that is flagged as a jump, provided it is at
+	// least trendJumpMinBytes so noise around zero is not flagged
+	trendJumpRatio    = 0.10
+	trendJumpMinBytes = 1.0
+)
+
+// storedRun is the structured form of the results of one export
+type storedRun struct {
+	Time  time.Time    `json:"time"`
+	Tests []storedTest `json:"tests"`
+}
+
+// storedTest is one test of a stored run with its headline saving
+type storedTest struct {
+	Name          string       `json:"name"`
+	Seed          int64        `json:"seed"`
+	Saved         uint64       `json:"saved"`
+	SavingPercent float64      `json:"savingPercent"`
+	Types         []storedType `json:"types"`
+
+	// Variants names the sides of the comparison when they are not the default
+	Variants *memory.Variants `json:"variants,omitempty"`
+}
+
+// storedType is one row of a stored test. Single-type tests have one row
+// named Standard, as in the report table.
+type storedType struct {
+	Name           string  `json:"name"`
+	ObjectCount    int     `json:"objectCount,omitempty"`
+	Optimized      uint64  `json:"optimized"`
+	Unoptimized    uint64  `json:"unoptimized"`
+	Saved          uint64  `json:"saved"`
+	SavingPercent  float64 `json:"savingPercent"`
+	SavedPerObject float64 `json:"savedPerObject"`
+}
+
+// newStoredRun converts the results of a run to their stored form
+func newStoredRun(results []memory.TestResult, now time.Time) storedRun {
+	run := storedRun{Time: now}
+	for _, r := range results {
+		summary := summarize(r, "")
+		test := storedTest{
+			Name:          r.Name,
+			Seed:          r.Seed,
+			Saved:         summary.Saved,
+			SavingPercent: summary.SavingPercent,
+		}
+		if variants := r.Variants(); variants != memory.DefaultVariants {
+			test.Variants = &variants
+		}
+
+		if typeResults, ok := r.OtherStats["TypeResults"].(map[string]map[string]interface{}); ok {
+			for typeName, typeData := range typeResults {
+				count, _ := typeData["ObjectCount"].(int)
+				optMem, _ := typeData["OptimizedMemory"].(uint64)
+				unoptMem, _ := typeData["UnoptimizedMemory"].(uint64)
+				saved, _ := typeData["MemorySaved"].(uint64)
+				savingPct, _ := typeData["SavingPercent"].(float64)
+				perObject, _ := typeData["PerObjectSaving"].(float64)
+				test.Types = append(test.Types, storedType{typeName, count, optMem, unoptMem, saved, savingPct, perObject})
+			}
+			sort.Slice(test.Types, func(i, j int) bool { return test.Types[i].Name < test.Types[j].Name })
+		} else {
+			optMem, _ := r.OtherStats["OptimizedMemory"].(uint64)
+			unoptMem, _ := r.OtherStats["UnoptimizedMemory"].(uint64)
+			test.Types = append(test.Types, storedType{
+				Name:           "Standard",
+				Optimized:      optMem,
+				Unoptimized:    unoptMem,
+				Saved:          r.MemoryUsed,
+				SavingPercent:  summary.SavingPercent,
+				SavedPerObject: r.PerObjectSize,
+			})
+		}
+
+		run.Tests = append(run.Tests, test)
+	}
+	return run
+}
+
+// saveRun writes a run to the history directory of its date
+func saveRun(dateDir string, run storedRun) (string, error) {
+	data, err := json.MarshalIndent(run, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	path := filepath.Join(dateDir, "results-"+run.Time.Format("150405")+".json")
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write structured results: %w", err)
+	}
+	return path, nil
+}
+
+// loadRuns reads the runs stored in every history directory, oldest first
+func loadRuns(baseDir string) ([]storedRun, error) {
+	files, err := filepath.Glob(filepath.Join(baseDir, "history", "*", resultsPattern))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read stored results: %w", err)
+	}
+
+	runs := make([]storedRun, 0, len(files))
+	for _, file := range files {
+		data, err := os.ReadFile(file)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read stored results: %w", err)
+		}
+		var run storedRun
+		if err := json.Unmarshal(data, &run); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", file, err)
+		}
+		runs = append(runs, run)
+	}
+	sort.Slice(runs, func(i, j int) bool { return runs[i].Time.Before(runs[j].Time) })
+	return runs, nil
+}
+
+// LoadResults reads a structured results file saved by an export, such as
+// docs/history/<date>/results-<time>.json, back into test results. Layouts
+// are not stored, so the results have none.
+func LoadResults(path string) ([]memory.TestResult, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read results: %w", err)
+	}
+	var run storedRun
+	if err := json.Unmarshal(data, &run); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+
+	results := make([]memory.TestResult, 0, len(run.Tests))
+	for _, test := range run.Tests {
+		r := memory.TestResult{
+			Name:       test.Name,
+			MemoryUsed: test.Saved,
+			Seed:       test.Seed,
+			OtherStats: make(map[string]interface{}),
+		}
+		if test.Variants != nil {
+			r.OtherStats["Variants"] = *test.Variants
+		}
+
+		if len(test.Types) == 1 && test.Types[0].Name == "Standard" {
+			typ := test.Types[0]
+			r.PerObjectSize = typ.SavedPerObject
+			r.OtherStats["OptimizedMemory"] = typ.Optimized
+			r.OtherStats["UnoptimizedMemory"] = typ.Unoptimized
+			r.OtherStats["MemorySavingPercent"] = typ.SavingPercent
+		} else {
+			typeResults := make(map[string]map[string]interface{}, len(test.Types))
+			for _, typ := range test.Types {
+				typeResults[typ.Name] = memory.NewTypeResult(typ.ObjectCount, typ.Optimized, typ.Unoptimized)
+			}
+			r.OtherStats["TypeResults"] = typeResults
+			r.OtherStats["TotalSaving"] = test.Saved
+		}
+
+		results = append(results, r)
+	}
+	return results, nil
+}
+
+// trendsPage is the data the trends template is rendered with
+type trendsPage struct {
+	Title     string
+	Generated string
+	Styles    template.CSS
+	Runs      int
+	Series    []trendSeries
+
+	// JumpPoints, JumpRatio and JumpMinBytes are the thresholds jumps are
+	// flagged at
+	JumpPoints   float64
+	JumpRatio    float64
+	JumpMinBytes float64
+}
+
+// trendSeries is the history of one type of one test
+type trendSeries struct {
+	Test   string
+	Type   string
+	Points []trendPoint
+	Jumps  int
+
+	// SavingChart and PerObjectChart plot the saving percent and the bytes
+	// saved per object over the runs
+	SavingChart    lineChart
+	PerObjectChart lineChart
+}
+
+// trendPoint is one run of a series with its change from the previous run
+type trendPoint struct {
+	Time           string
+	Seed           int64
+	SavingPercent  float64
+	SavedPerObject float64
+	SavingDelta    float64
+	PerObjectDelta float64
+
+	// Jump is set when the change from the previous run crosses a threshold
+	Jump bool
+}
+
+// newTrendsPage collects a series for every test and type in the runs
+func newTrendsPage(runs []storedRun) trendsPage {
+	page := trendsPage{
+		Title:        "Memory Allocation Test Trends",
+		Generated:    time.Now().Format("2006-01-02 15:04:05"),
+		Styles:       template.CSS(reportCSS),
+		Runs:         len(runs),
+		JumpPoints:   trendJumpPoints,
+		JumpRatio:    trendJumpRatio * 100,
+		JumpMinBytes: trendJumpMinBytes,
+	}
+
+	index := make(map[[2]string]int)
+	for _, run := range runs {
+		for _, test := range run.Tests {
+			for _, typ := range test.Types {
+				key := [2]string{test.Name, typ.Name}
+				i, ok := index[key]
+				if !ok {
+					i = len(page.Series)
+					index[key] = i
+					page.Series = append(page.Series, trendSeries{Test: test.Name, Type: typ.Name})
+				}
+				page.Series[i].add(trendPoint{
+					Time:           run.Time.Format("2006-01-02 15:04:05"),
+					Seed:           test.Seed,
+					SavingPercent:  typ.SavingPercent,
+					SavedPerObject: typ.SavedPerObject,
+				})
+			}
+		}
+	}
+
+	sort.Slice(page.Series, func(i, j int) bool {
+		a, b := page.Series[i], page.Series[j]
+		if a.Test != b.Test {
+			return a.Test < b.Test
+		}
+		return a.Type < b.Type
+	})
+
+	for i := range page.Series {
+		s := &page.Series[i]
+		labels := make([]string, len(s.Points))
+		saving := make([]float64, len(s.Points))
+		perObject := make([]float64, len(s.Points))
+		jumps := make([]bool, len(s.Points))
+		for j, p := range s.Points {
+			labels[j], saving[j], perObject[j], jumps[j] = p.Time, p.SavingPercent, p.SavedPerObject, p.Jump
+		}
+		s.SavingChart = newLineChart("Saving percent", labels, saving, jumps, func(v float64) string {
+			return fmt.Sprintf("%.1f%%", v)
+		})
+		s.PerObjectChart = newLineChart("Bytes saved per object", labels, perObject, jumps, func(v float64) string {
+			return fmt.Sprintf("%.1f B", v)
+		})
+	}
+
+	return page
+}
+
+// add appends a point, flagging it if it jumped from the previous point
+func (s *trendSeries) add(p trendPoint) {
+	if n := len(s.Points); n > 0 {
+		prev := s.Points[n-1]
+		p.SavingDelta = p.SavingPercent - prev.SavingPercent
+		p.PerObjectDelta = p.SavedPerObject - prev.SavedPerObject
+		perObjectChange := math.Abs(p.PerObjectDelta)
+		p.Jump = math.Abs(p.SavingDelta) >= trendJumpPoints ||
+			(perObjectChange >= trendJumpMinBytes && perObjectChange > trendJumpRatio*math.Abs(prev.SavedPerObject))
+		if p.Jump {
+			s.Jumps++
+		}
+	}
+	s.Points = append(s.Points, p)
+}
+
+// generateTrendsPage creates the trends page from the runs stored in baseDir
+func (h *HTMLVisualizer) generateTrendsPage(baseDir string) error {
+	runs, err := loadRuns(baseDir)
+	if err != nil {
+		return err
+	}
+
+	html, err := h.render(trendsTemplate, newTrendsPage(runs))
+	if err != nil {
+		return err
+	}
+
+	trendsPath := filepath.Join(baseDir, trendsFile)
+	if err := os.WriteFile(trendsPath, html.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write trends page: %w", err)
+	}
+	fmt.Printf("GitHub Pages trends page generated: %s\n", trendsPath)
+
+	return nil
+}
@@ -0,0 +1,417 @@
+package visualizer
+
+import (
+	"bytes"
+	"fmt"
+	"mem-tests/pkg/memory"
+	"mem-tests/pkg/term"
+	"os"
+	"sort"
+	"strings"
+	"unicode/utf8"
+)
+
+// ANSI sequences that control the screen of the TUI. Text attributes come
+// from term.Style, so they follow -no-color and NO_COLOR.
+const (
+	tuiEnter = "\x1b[?1049h\x1b[?25l" // alternate screen, hidden cursor
+	tuiLeave = "\x1b[?25h\x1b[?1049l"
+	tuiHome  = "\x1b[H"
+)
+
+// tuiHelp is the key help shown at the bottom of the screen
+const tuiHelp = "↑/↓ j/k select  PgUp/PgDn u/d scroll  g/G first/last  s sort  r reverse  q quit"
+
+// tuiSort is an order of the test list
+type tuiSort int
+
+const (
+	sortBySaved tuiSort = iota
+	sortByPercent
+	sortBySize
+	sortByName
+)
+
+// tuiSortNames name the orders in the title bar
+var tuiSortNames = [...]string{"saving", "saving %", "size", "name"}
+
+// TUIVisualizer browses results in a full-screen terminal UI, with the test
+// list on the left and the details of the selected test on the right. It
+// needs only raw terminal handling, so it also works over SSH.
+type TUIVisualizer struct {
+	// Style colors the screen. The zero Style draws it without attributes.
+	Style term.Style
+}
+
+// tuiLine is a line of the screen with the style it is drawn with, or nil
+// to draw it plain
+type tuiLine struct {
+	text  string
+	style func(string) string
+}
+
+// tuiState is the browsing state of the TUI
+type tuiState struct {
+	results   []memory.TestResult
+	summaries []testSummary
+	style     term.Style
+
+	// order holds the indexes of the results in the current sort order,
+	// and selected is the position of the selected result in it
+	order    []int
+	selected int
+
+	// scroll is the first line of the details shown
+	scroll int
+
+	sortBy     tuiSort
+	descending bool
+}
+
+// Visualize implements the Visualizer interface by opening the TUI on the
+// terminal and returning when the user quits
+func (t *TUIVisualizer) Visualize(results []memory.TestResult) error {
+	if len(results) == 0 {
+		return fmt.Errorf("no results to visualize")
+	}
+	if !term.IsTerminal(os.Stdin) || !term.IsTerminal(os.Stdout) {
+		return fmt.Errorf("the TUI needs an interactive terminal")
+	}
+
+	restore, err := term.MakeRaw(os.Stdin)
+	if err != nil {
+		return fmt.Errorf("failed to set up the terminal: %w", err)
+	}
+	defer restore()
+
+	fmt.Print(tuiEnter)
+	defer fmt.Print(tuiLeave)
+
+	state := &tuiState{results: results, style: t.Style, descending: true}
+	for _, r := range results {
+		state.summaries = append(state.summaries, summarize(r, ""))
+	}
+	state.sort()
+
+	// Keys are read in the background so a resize can redraw the screen. The
+	// reader waits for each input to be handled before reading again, so no
+	// read is left pending on the restored terminal once the user quits.
+	keys := make(chan string)
+	next := make(chan struct{})
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		defer close(keys)
+		buf := make([]byte, 64)
+		for {
+			n, err := os.Stdin.Read(buf)
+			if err != nil {
+				return
+			}
+			select {
+			case keys <- string(buf[:n]):
+			case <-done:
+				return
+			}
+			select {
+			case <-next:
+			case <-done:
+				return
+			}
+		}
+	}()
+	resize := make(chan os.Signal, 1)
+	term.NotifyResize(resize)
+	defer term.StopResize(resize)
+
+	for {
+		width, height, ok := term.Size(os.Stdout)
+		if !ok {
+			width, height = term.DefaultWidth, 24
+		}
+		bodyHeight := max(1, height-2)
+		os.Stdout.WriteString(state.render(width, height))
+
+		select {
+		case input, ok := <-keys:
+			if !ok {
+				return nil
+			}
+			for _, key := range splitKeys(input) {
+				if state.handle(key, bodyHeight) {
+					return nil
+				}
+			}
+			next <- struct{}{}
+		case <-resize:
+		}
+	}
+}
+
+// splitKeys splits terminal input into keys, keeping escape sequences whole
+func splitKeys(input string) []string {
+	var keys []string
+	for len(input) > 0 {
+		n := 1
+		if input[0] == '\x1b' && len(input) > 2 && (input[1] == '[' || input[1] == 'O') {
+			// A sequence ends at its first letter or ~
+			n = 2
+			for n < len(input) {
+				c := input[n]
+				n++
+				if c == '~' || (c >= 'A' && c <= 'Z') || (c >= 'a' && c <= 'z') {
+					break
+				}
+			}
+		} else if input[0] >= utf8.RuneSelf {
+			_, n = utf8.DecodeRuneInString(input)
+		}
+		keys = append(keys, input[:n])
+		input = input[n:]
+	}
+	return keys
+}
+
+// handle applies a key and reports whether the user quit
+func (s *tuiState) handle(key string, bodyHeight int) bool {
+	switch key {
+	case "q", "Q", "\x03":
+		return true
+	case "\x1b[A", "\x1bOA", "k":
+		s.selectResult(s.selected - 1)
+	case "\x1b[B", "\x1bOB", "j":
+		s.selectResult(s.selected + 1)
+	case "\x1b[H", "\x1bOH", "\x1b[1~", "g":
+		s.selectResult(0)
+	case "\x1b[F", "\x1bOF", "\x1b[4~", "G":
+		s.selectResult(len(s.order) - 1)
+	case "\x1b[5~", "u":
+		s.scroll = max(0, s.scroll-bodyHeight/2)
+	case "\x1b[6~", "d", " ":
+		s.scroll += bodyHeight / 2
+	case "s":
+		s.sortBy = (s.sortBy + 1) % tuiSort(len(tuiSortNames))
+		// Names read best A to Z, the numbers largest first
+		s.descending = s.sortBy != sortByName
+		s.sort()
+	case "r":
+		s.descending = !s.descending
+		s.sort()
+	}
+	return false
+}
+
+// selectResult selects the result at a position in the list
+func (s *tuiState) selectResult(pos int) {
+	pos = max(0, min(pos, len(s.order)-1))
+	if pos != s.selected {
+		s.selected = pos
+		s.scroll = 0
+	}
+}
+
+// sort orders the list by the current sort, keeping the selected result
+func (s *tuiState) sort() {
+	current := -1
+	if len(s.order) > 0 {
+		current = s.order[s.selected]
+	}
+
+	s.order = make([]int, len(s.results))
+	for i := range s.order {
+		s.order[i] = i
+	}
+	less := func(a, b int) bool {
+		sa, sb := s.summaries[a], s.summaries[b]
+		switch s.sortBy {
+		case sortBySaved:
+			return sa.Saved < sb.Saved
+		case sortByPercent:
+			return sa.SavingPercent < sb.SavingPercent
+		case sortBySize:
+			return sa.Unoptimized < sb.Unoptimized
+		}
+		return sa.Name < sb.Name
+	}
+	sort.SliceStable(s.order, func(i, j int) bool {
+		if s.descending {
+			return less(s.order[j], s.order[i])
+		}
+		return less(s.order[i], s.order[j])
+	})
+
+	for pos, i := range s.order {
+		if i == current {
+			s.selected = pos
+		}
+	}
+}
+
+// render draws the whole screen
+func (s *tuiState) render(width, height int) string {
+	listWidth := 0
+	for _, r := range s.results {
+		listWidth = max(listWidth, utf8.RuneCountInString(r.Name)+10)
+	}
+	listWidth = min(listWidth, width/3)
+	detailWidth := max(1, width-listWidth-3)
+	bodyHeight := max(1, height-2)
+
+	details := s.details(s.results[s.order[s.selected]], detailWidth)
+	s.scroll = max(0, min(s.scroll, len(details)-bodyHeight))
+
+	order := "ascending"
+	if s.descending {
+		order = "descending"
+	}
+	title := fmt.Sprintf(" Memory Allocation Test Results - %d tests - sorted by %s, %s", len(s.results), tuiSortNames[s.sortBy], order)
+
+	// Scroll the list so the selected test is visible
+	listTop := max(0, s.selected-bodyHeight+1)
+
+	var screen strings.Builder
+	screen.WriteString(tuiHome)
+	screen.WriteString(s.style.Reverse(fit(title, width)) + "\r\n")
+	for row := 0; row < bodyHeight; row++ {
+		// Test list
+		if pos := listTop + row; pos < len(s.order) {
+			summary := s.summaries[s.order[pos]]
+			pct := fmt.Sprintf("%6.2f%%", summary.SavingPercent)
+			// The marker shows the selection when the style draws no attributes
+			marker := " "
+			if pos == s.selected {
+				marker = ">"
+			}
+			line := fit(marker+summary.Name, max(0, listWidth-len(pct)-1)) + " " + pct
+			if pos == s.selected {
+				line = s.style.Reverse(line)
+			}
+			screen.WriteString(line)
+		} else {
+			screen.WriteString(strings.Repeat(" ", listWidth))
+		}
+
+		screen.WriteString(" " + s.style.Dim("│") + " ")
+
+		// Details of the selected test
+		var line tuiLine
+		if i := s.scroll + row; i < len(details) {
+			line = details[i]
+		}
+		if line.style != nil {
+			screen.WriteString(line.style(fit(line.text, detailWidth)))
+		} else {
+			screen.WriteString(fit(line.text, detailWidth))
+		}
+		screen.WriteString("\r\n")
+	}
+	screen.WriteString(s.style.Dim(fit(" "+tuiHelp, width)))
+	return screen.String()
+}
+
+// details lays out the per-variant memory, the per-type table and the
+// layout maps of a result in lines of at most width runes
+func (s *tuiState) details(r memory.TestResult, width int) []tuiLine {
+	summary := summarize(r, "")
+	saving := func(text string) string { return s.style.Signed(summary.SavingPercent, text) }
+
+	lines := []tuiLine{
+		{r.Name, s.style.Bold},
+		{fmt.Sprintf("Seed: %d", r.Seed), nil},
+		{fmt.Sprintf("Saving: %s (%d bytes), %.2f%%", memory.FormatBytes(summary.Saved), summary.Saved, summary.SavingPercent), saving},
+		{"", nil},
+	}
+	text := func(block string) {
+		for _, line := range strings.Split(strings.TrimRight(block, "\n"), "\n") {
+			lines = append(lines, tuiLine{line, nil})
+		}
+	}
+	heading := func(title string) {
+		lines = append(lines, tuiLine{"", nil}, tuiLine{title, s.style.Bold})
+	}
+	byteMaps := func(stats map[string]interface{}) {
+		variants := layoutVariants(stats, r.Variants())
+		if len(variants) == 0 {
+			return
+		}
+		var buf bytes.Buffer
+		writeByteMaps(&buf, variants, width)
+		text(buf.String())
+	}
+
+	typeResults, ok := r.OtherStats["TypeResults"].(map[string]map[string]interface{})
+	if !ok {
+		// Per-variant memory of a single-type test
+		table := term.NewTable("Variant", "Memory", "Bytes", "Struct size").AlignRight(1, 2, 3)
+		names := r.Variants()
+		for _, variant := range []struct{ key, name string }{
+			{"Optimized", names.Optimized},
+			{"Unoptimized", names.Unoptimized},
+		} {
+			mem, _ := r.OtherStats[variant.key+"Memory"].(uint64)
+			structSize := "-"
+			if size, ok := r.OtherStats[variant.key+"StructSize"].(uintptr); ok {
+				structSize = fmt.Sprintf("%d bytes", size)
+			}
+			table.Row(variant.name, memory.FormatBytes(mem), fmt.Sprint(mem), structSize)
+		}
+		var buf bytes.Buffer
+		table.Write(&buf, "", term.Style{})
+		lines = append(lines, tuiLine{"Memory by variant", s.style.Bold})
+		text(buf.String())
+		if r.PerObjectSize > 0 {
+			lines = append(lines, tuiLine{fmt.Sprintf("Saved per object: %.2f bytes", r.PerObjectSize), nil})
+		}
+
+		if len(layoutVariants(r.OtherStats, r.Variants())) > 0 {
+			heading("Layout")
+			byteMaps(r.OtherStats)
+		}
+		return lines
+	}
+
+	typeNames := make([]string, 0, len(typeResults))
+	for typeName := range typeResults {
+		typeNames = append(typeNames, typeName)
+	}
+	sort.Strings(typeNames)
+
+	variants := r.Variants()
+	table := term.NewTable("Type", "Objects", variants.Optimized, variants.Unoptimized, "Saved", "Saving").AlignRight(1, 2, 3, 4, 5)
+	for _, typeName := range typeNames {
+		typeData := typeResults[typeName]
+		count, _ := typeData["ObjectCount"].(int)
+		optMem, _ := typeData["OptimizedMemory"].(uint64)
+		unoptMem, _ := typeData["UnoptimizedMemory"].(uint64)
+		saved, _ := typeData["MemorySaved"].(uint64)
+		pct, _ := typeData["SavingPercent"].(float64)
+		table.Row(typeName, fmt.Sprint(count), memory.FormatBytes(optMem), memory.FormatBytes(unoptMem),
+			memory.FormatBytes(saved), fmt.Sprintf("%.2f%%", pct))
+	}
+	var buf bytes.Buffer
+	table.Write(&buf, "", term.Style{})
+	lines = append(lines, tuiLine{"Memory by type", s.style.Bold})
+	text(buf.String())
+
+	for _, typeName := range typeNames {
+		if len(layoutVariants(typeResults[typeName], variants)) == 0 {
+			continue
+		}
+		heading(typeName + " layout")
+		byteMaps(typeResults[typeName])
+	}
+	return lines
+}
+
+// fit truncates or pads text to exactly width runes
+func fit(text string, width int) string {
+	n := utf8.RuneCountInString(text)
+	if n <= width {
+		return text + strings.Repeat(" ", width-n)
+	}
+	if width <= 0 {
+		return ""
+	}
+	runes := []rune(text)
+	return string(runes[:width-1]) + "…"
+}
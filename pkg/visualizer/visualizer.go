@@ -2,14 +2,28 @@ package visualizer
 
 import (
 	"bytes"
+	_ "embed"
 	"fmt"
+	"html/template"
 	"mem-tests/pkg/memory"
+	"mem-tests/pkg/term"
 	"os"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 )
 
+// reportCSS styles the HTML report. It is inlined so the report needs no network.
+//
+//go:embed assets/report.css
+var reportCSS string
+
+// reportJS adds the raw byte toggle to the HTML report
+//
+//go:embed assets/report.js
+var reportJS string
+
 // Visualizer is responsible for creating visual representations of test results
 type Visualizer interface {
 	// Visualize takes test results and renders them in the visualizer's format
@@ -17,7 +31,14 @@ type Visualizer interface {
 }
 
 // TerminalVisualizer displays results in the terminal with ASCII bars
-type TerminalVisualizer struct{}
+type TerminalVisualizer struct {
+	// Width is the number of columns to fit the output to. If it is zero
+	// the width of the terminal is detected.
+	Width int
+
+	// Style colors savings and regressions
+	Style term.Style
+}
 
 // Visualize implements the Visualizer interface for terminal output
 func (t *TerminalVisualizer) Visualize(results []memory.TestResult) error {
@@ -36,57 +57,90 @@ func (t *TerminalVisualizer) Visualize(results []memory.TestResult) error {
 		}
 	}
 
-	// Terminal width (adjust if needed)
-	termWidth := 80
-	maxBarWidth := termWidth - 40 // Leave space for labels
+	termWidth := t.Width
+	if termWidth <= 0 {
+		termWidth = term.Width(os.Stdout)
+	}
+
+	// Labels are as wide as the longest one, and the bars take what is left
+	labelWidth := len("Memory Saving:")
+	for _, r := range results {
+		if _, ok := r.OtherStats["TypeResults"]; !ok {
+			labelWidth = max(labelWidth, len(r.Name+" (Unopt)"))
+		} else {
+			variants := r.Variants()
+			labelWidth = max(labelWidth, len(variants.Optimized), len(variants.Unoptimized))
+		}
+	}
+	const memoryWidth = 12
+	maxBarWidth := max(10, termWidth-labelWidth-memoryWidth-2)
+
+	bar := func(label string, value, scale uint64, color func(string) string) {
+		barLength := 0
+		if scale > 0 {
+			barLength = int(float64(value) / float64(scale) * float64(maxBarWidth))
+		}
+		fmt.Printf("%-*s %*s %s\n", labelWidth, label, memoryWidth, memory.FormatBytes(value), color(strings.Repeat("█", barLength)))
+	}
+	saving := func(label string, pct float64) {
+		text := fmt.Sprintf("%*.2f%%", memoryWidth-1, pct)
+		fmt.Printf("%-*s %s\n", labelWidth, label, t.Style.Signed(pct, text))
+	}
 
-	fmt.Println("\n=== Memory Usage Visualization ===")
+	fmt.Println(t.Style.Heading("\n=== Memory Usage Visualization ==="))
 	fmt.Println(strings.Repeat("=", termWidth))
-	fmt.Printf("%-20s %-15s %s\n", "Test", "Memory", "Bar")
+	fmt.Printf("%-*s %*s %s\n", labelWidth, "Test", memoryWidth, "Memory", "Bar")
 	fmt.Println(strings.Repeat("-", termWidth))
 
 	for _, r := range results {
 		// Check for multiple type results
 		if typeResults, ok := r.OtherStats["TypeResults"].(map[string]map[string]interface{}); ok {
-			fmt.Printf("=== %s ===\n", r.Name)
+			fmt.Println(t.Style.Heading(fmt.Sprintf("=== %s ===", r.Name)))
 
 			// Find max value for scaling within this test
 			var maxTypeVal uint64
-			for _, typeData := range typeResults {
+			typeNames := make([]string, 0, len(typeResults))
+			for typeName, typeData := range typeResults {
 				if v, ok := typeData["UnoptimizedMemory"].(uint64); ok && v > maxTypeVal {
 					maxTypeVal = v
 				}
+				if v, ok := typeData["OptimizedMemory"].(uint64); ok && v > maxTypeVal {
+					maxTypeVal = v
+				}
+				typeNames = append(typeNames, typeName)
 			}
+			sort.Strings(typeNames)
 
 			// Display each type result
-			for typeName, typeData := range typeResults {
-				fmt.Printf("--- %s ---\n", typeName)
+			variants := r.Variants()
+			for _, typeName := range typeNames {
+				typeData := typeResults[typeName]
+				fmt.Println(t.Style.Bold(fmt.Sprintf("--- %s ---", typeName)))
 
-				// Display optimized struct memory
+				// Display the memory of the optimized variant
 				if optMem, ok := typeData["OptimizedMemory"].(uint64); ok {
-					barLength := int(float64(optMem) / float64(maxTypeVal) * float64(maxBarWidth))
-					bar := strings.Repeat("█", barLength)
-					fmt.Printf("%-20s %-15s %s\n", "Optimized", memory.FormatBytes(optMem), bar)
+					bar(variants.Optimized, optMem, maxTypeVal, t.Style.Saving)
 				}
 
-				// Display unoptimized struct memory
+				// Display the memory of the baseline
 				if unoptMem, ok := typeData["UnoptimizedMemory"].(uint64); ok {
-					barLength := int(float64(unoptMem) / float64(maxTypeVal) * float64(maxBarWidth))
-					bar := strings.Repeat("█", barLength)
-					fmt.Printf("%-20s %-15s %s\n", "Unoptimized", memory.FormatBytes(unoptMem), bar)
+					bar(variants.Unoptimized, unoptMem, maxTypeVal, t.Style.Regression)
 				}
 
 				// Add a memory saving percentage
 				if savingPct, ok := typeData["SavingPercent"].(float64); ok {
-					fmt.Printf("Memory Saving: %.2f%%\n", savingPct)
+					saving("Memory Saving:", savingPct)
 				}
 
+				// Draw the struct layouts byte by byte
+				printLayouts(typeData, variants, termWidth)
+
 				fmt.Println(strings.Repeat("-", termWidth))
 			}
 
 			if totalSaving, ok := r.OtherStats["TotalSaving"].(uint64); ok {
 				fmt.Printf("Total Memory Saving: %s (%d bytes)\n",
-					memory.FormatBytes(totalSaving), totalSaving)
+					t.Style.Saving(memory.FormatBytes(totalSaving)), totalSaving)
 			}
 
 			continue
@@ -94,53 +148,92 @@ func (t *TerminalVisualizer) Visualize(results []memory.TestResult) error {
 
 		// Standard visualization for simple tests
 		if optMem, ok := r.OtherStats["OptimizedMemory"].(uint64); ok {
-			barLength := int(float64(optMem) / float64(maxVal) * float64(maxBarWidth))
-			bar := strings.Repeat("█", barLength)
-			fmt.Printf("%-20s %-15s %s\n", r.Name+" (Opt)", memory.FormatBytes(optMem), bar)
+			bar(r.Name+" (Opt)", optMem, maxVal, t.Style.Saving)
 		}
 
 		if unoptMem, ok := r.OtherStats["UnoptimizedMemory"].(uint64); ok {
-			barLength := int(float64(unoptMem) / float64(maxVal) * float64(maxBarWidth))
-			bar := strings.Repeat("█", barLength)
-			fmt.Printf("%-20s %-15s %s\n", r.Name+" (Unopt)", memory.FormatBytes(unoptMem), bar)
+			bar(r.Name+" (Unopt)", unoptMem, maxVal, t.Style.Regression)
 		}
 
 		if savingPct, ok := r.OtherStats["MemorySavingPercent"].(float64); ok {
-			fmt.Printf("%-20s %.2f%%\n", "Memory Saving:", savingPct)
+			saving("Memory Saving:", savingPct)
 		}
 
+		printLayouts(r.OtherStats, r.Variants(), termWidth)
+
 		fmt.Println(strings.Repeat("-", termWidth))
 	}
 
 	return nil
 }
 
-// HTMLVisualizer generates HTML visualizations with JavaScript charts
+// DefaultOutputFile and DefaultPagesDir are where the HTML visualizer writes
+// the report and the GitHub Pages export unless configured otherwise
+const (
+	DefaultOutputFile = "memory_test_results.html"
+	DefaultPagesDir   = "docs"
+)
+
+// HTMLVisualizer generates self-contained HTML visualizations with inline SVG charts
 type HTMLVisualizer struct {
 	// ExportToGitHubPages controls whether to also export results to GitHub Pages directory
 	ExportToGitHubPages bool
+
+	// OutputFile is the path of the combined report, DefaultOutputFile if empty
+	OutputFile string
+
+	// PagesDir is the GitHub Pages directory, DefaultPagesDir if empty
+	PagesDir string
+
+	// Retention selects the history runs kept after each export. The zero
+	// value keeps the whole history.
+	Retention Retention
+
+	// TemplateDir is a directory of templates that override the embedded
+	// defaults by name. It is empty to use the defaults only.
+	TemplateDir string
+
+	// templates are loaded on first use
+	templates *template.Template
 }
 
 // Visualize implements the Visualizer interface for HTML output
 func (h *HTMLVisualizer) Visualize(results []memory.TestResult) error {
 	fmt.Println("Generating HTML visualization...")
 
-	// Create HTML content
-	htmlContent, err := h.generateHTMLContent(results)
+	outputFile := h.OutputFile
+	if outputFile == "" {
+		outputFile = DefaultOutputFile
+	}
+
+	// Create HTML content. Links are relative, so the report also works
+	// when opened straight from disk.
+	var indexLink string
+	if h.ExportToGitHubPages {
+		link, err := filepath.Rel(filepath.Dir(outputFile), filepath.Join(h.pagesDir(), "index.html"))
+		if err != nil {
+			return fmt.Errorf("failed to link the report to the GitHub Pages index: %w", err)
+		}
+		indexLink = filepath.ToSlash(link)
+	}
+	htmlContent, err := h.generateHTMLContent(results, indexLink)
 	if err != nil {
 		return err
 	}
 
-	// Write to default file
-	defaultFile := "memory_test_results.html"
-	if err := os.WriteFile(defaultFile, htmlContent.Bytes(), 0644); err != nil {
+	if dir := filepath.Dir(outputFile); dir != "." {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+	}
+	if err := os.WriteFile(outputFile, htmlContent.Bytes(), 0644); err != nil {
 		return fmt.Errorf("failed to write HTML file: %w", err)
 	}
-	fmt.Printf("HTML visualization saved to %s\n", defaultFile)
+	fmt.Printf("HTML visualization saved to %s\n", outputFile)
 
 	// Export to GitHub Pages if enabled
 	if h.ExportToGitHubPages {
-		if err := h.exportToGitHubPages(results, htmlContent); err != nil {
+		if err := h.exportToGitHubPages(results); err != nil {
 			return fmt.Errorf("failed to export to GitHub Pages: %w", err)
 		}
 	}
@@ -148,347 +241,133 @@ func (h *HTMLVisualizer) Visualize(results []memory.TestResult) error {
 	return nil
 }
 
-// generateHTMLContent creates the HTML content for visualization
-func (h *HTMLVisualizer) generateHTMLContent(results []memory.TestResult) (*bytes.Buffer, error) {
-	var html bytes.Buffer
+// pagesDir returns the GitHub Pages directory
+func (h *HTMLVisualizer) pagesDir() string {
+	if h.PagesDir == "" {
+		return DefaultPagesDir
+	}
+	return h.PagesDir
+}
 
-	// Generate timestamp for report
-	timestamp := time.Now().Format("2006-01-02 15:04:05")
-
-	// HTML header and CSS
-	html.WriteString(`<!DOCTYPE html>
-<html>
-<head>
-    <title>Memory Allocation Test Results</title>
-    <meta charset="UTF-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <script src="https://cdn.jsdelivr.net/npm/chart.js"></script>
-    <style>
-        body { font-family: Arial, sans-serif; margin: 20px; }
-        .container { max-width: 1200px; margin: 0 auto; }
-        .chart-container { height: 400px; margin-bottom: 40px; }
-        table { border-collapse: collapse; width: 100%; margin: 20px 0; }
-        th, td { border: 1px solid #ddd; padding: 8px; text-align: left; }
-        th { background-color: #f2f2f2; }
-        tr:nth-child(even) { background-color: #f9f9f9; }
-        h1, h2 { color: #333; }
-        .memory-cell { white-space: nowrap; }
-        .footer { margin-top: 30px; font-size: 0.8em; color: #666; border-top: 1px solid #ddd; padding-top: 10px; }
-        .nav-links { margin: 20px 0; }
-        .nav-links a { margin-right: 15px; }
-    </style>
-</head>
-<body>
-    <div class="container">
-        <h1>Memory Allocation Test Results</h1>
-        <div class="nav-links">
-            <a href="../index.html">All Tests</a>
-        </div>
-        <p>Generated on: `)
-
-	// Add the timestamp separately
-	html.WriteString(timestamp)
-	html.WriteString(`</p>`)
-
-	// Generate charts and tables for each test
-	for i, r := range results {
-		// Add chart container
-		html.WriteString(fmt.Sprintf(`
-        <h2>%s</h2>
-        <div class="chart-container">
-            <canvas id="chart%d"></canvas>
-        </div>
-`, r.Name, i))
-
-		// Generate table for results
-		html.WriteString(`
-        <table>
-            <tr>
-                <th>Type</th>
-                <th>Optimized Memory</th>
-                <th>Unoptimized Memory</th>
-                <th>Memory Saving</th>
-                <th>Saving Percentage</th>
-            </tr>
-`)
+// generateHTMLContent creates the HTML content for visualization.
+// indexLink is the path of the GitHub Pages index relative to the page.
+func (h *HTMLVisualizer) generateHTMLContent(results []memory.TestResult, indexLink string) (*bytes.Buffer, error) {
+	return h.render(reportTemplate, newReportPage("Memory Allocation Test Results", results, indexLink))
+}
 
-		// Check for multiple type results
-		if typeResults, ok := r.OtherStats["TypeResults"].(map[string]map[string]interface{}); ok {
-			for typeName, typeData := range typeResults {
-				optMem, _ := typeData["OptimizedMemory"].(uint64)
-				unoptMem, _ := typeData["UnoptimizedMemory"].(uint64)
-				saved, _ := typeData["MemorySaved"].(uint64)
-				savingPct, _ := typeData["SavingPercent"].(float64)
-
-				html.WriteString(fmt.Sprintf(`
-            <tr>
-                <td>%s</td>
-                <td class="memory-cell">%s <span class="bytes-value">(%d bytes)</span></td>
-                <td class="memory-cell">%s <span class="bytes-value">(%d bytes)</span></td>
-                <td class="memory-cell">%s <span class="bytes-value">(%d bytes)</span></td>
-                <td>%.2f%%</td>
-            </tr>
-`, typeName,
-					memory.FormatBytes(optMem), optMem,
-					memory.FormatBytes(unoptMem), unoptMem,
-					memory.FormatBytes(saved), saved,
-					savingPct))
-			}
+// generateTestPage creates the GitHub Pages page of a single test, with a
+// field by field breakdown of every layout
+func (h *HTMLVisualizer) generateTestPage(result memory.TestResult, indexLink string) (*bytes.Buffer, error) {
+	page := newReportPage(result.Name, []memory.TestResult{result}, indexLink)
+	page.Breakdown = true
+	return h.render(reportTemplate, page)
+}
 
-			// Add total row
-			if totalSaving, ok := r.OtherStats["TotalSaving"].(uint64); ok {
-				html.WriteString(fmt.Sprintf(`
-            <tr style="font-weight: bold;">
-                <td>Total</td>
-                <td>-</td>
-                <td>-</td>
-                <td class="memory-cell">%s <span class="bytes-value">(%d bytes)</span></td>
-                <td>-</td>
-            </tr>
-`, memory.FormatBytes(totalSaving), totalSaving))
-			}
-		} else {
-			// Standard results
-			optMem, _ := r.OtherStats["OptimizedMemory"].(uint64)
-			unoptMem, _ := r.OtherStats["UnoptimizedMemory"].(uint64)
-			savingPct, _ := r.OtherStats["MemorySavingPercent"].(float64)
-
-			html.WriteString(fmt.Sprintf(`
-            <tr>
-                <td>Standard</td>
-                <td class="memory-cell">%s <span class="bytes-value">(%d bytes)</span></td>
-                <td class="memory-cell">%s <span class="bytes-value">(%d bytes)</span></td>
-                <td class="memory-cell">%s <span class="bytes-value">(%d bytes)</span></td>
-                <td>%.2f%%</td>
-            </tr>
-`, memory.FormatBytes(optMem), optMem,
-				memory.FormatBytes(unoptMem), unoptMem,
-				memory.FormatBytes(r.MemoryUsed), r.MemoryUsed,
-				savingPct))
+// render executes the named template, loading the templates on first use
+func (h *HTMLVisualizer) render(name string, data any) (*bytes.Buffer, error) {
+	if h.templates == nil {
+		tmpl, err := loadTemplates(h.TemplateDir)
+		if err != nil {
+			return nil, err
 		}
+		h.templates = tmpl
+	}
 
-		html.WriteString(`
-        </table>
-`)
-	}
-
-	// Add JavaScript for charts and formatting
-	html.WriteString(`
-        <script>
-            // Helper function to format bytes to human-readable format
-            function formatBytes(bytes, decimals = 2) {
-                if (bytes === 0) return '0 Bytes';
-                
-                const k = 1024;
-                const dm = decimals < 0 ? 0 : decimals;
-                const sizes = ['Bytes', 'KB', 'MB', 'GB', 'TB', 'PB', 'EB', 'ZB', 'YB'];
-                
-                const i = Math.floor(Math.log(bytes) / Math.log(k));
-                
-                return parseFloat((bytes / Math.pow(k, i)).toFixed(dm)) + ' ' + sizes[i];
-            }
-            
-            // Toggle byte values visibility
-            document.addEventListener('DOMContentLoaded', function() {
-                const bytesValues = document.querySelectorAll('.bytes-value');
-                let bytesVisible = false;
-                
-                // Add button to toggle byte values
-                const toggleBtn = document.createElement('button');
-                toggleBtn.innerText = 'Show Raw Bytes';
-                toggleBtn.style = 'margin: 20px 0; padding: 8px 16px;';
-                toggleBtn.onclick = function() {
-                    bytesVisible = !bytesVisible;
-                    bytesValues.forEach(el => {
-                        el.style.display = bytesVisible ? 'inline' : 'none';
-                    });
-                    this.innerText = bytesVisible ? 'Hide Raw Bytes' : 'Show Raw Bytes';
-                };
-                
-                // Initially hide byte values
-                bytesValues.forEach(el => {
-                    el.style.display = 'none';
-                });
-                
-                // Add button to page
-                document.querySelector('.container').insertBefore(toggleBtn, document.querySelector('h2'));
-`)
-
-	// Generate chart creation code for each test
-	for i, r := range results {
-		html.WriteString(fmt.Sprintf(`
-                // Chart for %s
-                new Chart(document.getElementById('chart%d'), {
-                    type: 'bar',
-                    data: {
-`, r.Name, i))
-
-		// Handle multiple type results
-		if typeResults, ok := r.OtherStats["TypeResults"].(map[string]map[string]interface{}); ok {
-			// Prepare labels and data
-			var labels []string
-			var optimizedData []uint64
-			var unoptimizedData []uint64
-
-			for typeName, typeData := range typeResults {
-				labels = append(labels, typeName)
-
-				if optMem, ok := typeData["OptimizedMemory"].(uint64); ok {
-					optimizedData = append(optimizedData, optMem)
-				} else {
-					optimizedData = append(optimizedData, 0)
-				}
-
-				if unoptMem, ok := typeData["UnoptimizedMemory"].(uint64); ok {
-					unoptimizedData = append(unoptimizedData, unoptMem)
-				} else {
-					unoptimizedData = append(unoptimizedData, 0)
-				}
-			}
-
-			// Generate labels JSON
-			html.WriteString("                        labels: [")
-			for j, label := range labels {
-				if j > 0 {
-					html.WriteString(", ")
-				}
-				html.WriteString(fmt.Sprintf("'%s'", label))
-			}
-			html.WriteString("],\n")
-
-			// Generate datasets
-			html.WriteString(`                        datasets: [
-                            {
-                                label: 'Optimized',
-                                backgroundColor: 'rgba(54, 162, 235, 0.8)',
-                                data: [`)
-
-			for j, data := range optimizedData {
-				if j > 0 {
-					html.WriteString(", ")
-				}
-				html.WriteString(fmt.Sprintf("%d", data))
-			}
-
-			html.WriteString(`]
-                            },
-                            {
-                                label: 'Unoptimized',
-                                backgroundColor: 'rgba(255, 99, 132, 0.8)',
-                                data: [`)
-
-			for j, data := range unoptimizedData {
-				if j > 0 {
-					html.WriteString(", ")
-				}
-				html.WriteString(fmt.Sprintf("%d", data))
-			}
-
-			html.WriteString(`]
-                            }
-                        ]
-`)
-		} else {
-			// Standard results
-			optMem, _ := r.OtherStats["OptimizedMemory"].(uint64)
-			unoptMem, _ := r.OtherStats["UnoptimizedMemory"].(uint64)
-
-			html.WriteString(fmt.Sprintf(`                        labels: ['%s'],
-                        datasets: [
-                            {
-                                label: 'Optimized',
-                                backgroundColor: 'rgba(54, 162, 235, 0.8)',
-                                data: [%d]
-                            },
-                            {
-                                label: 'Unoptimized',
-                                backgroundColor: 'rgba(255, 99, 132, 0.8)',
-                                data: [%d]
-                            }
-                        ]
-`, r.Name, optMem, unoptMem))
-		}
+	var html bytes.Buffer
+	if err := h.templates.ExecuteTemplate(&html, name, data); err != nil {
+		return nil, fmt.Errorf("failed to render %s: %w", name, err)
+	}
+	return &html, nil
+}
 
-		// Chart options with custom tooltips for human-readable sizes
-		html.WriteString(`                    },
-                    options: {
-                        responsive: true,
-                        maintainAspectRatio: false,
-                        scales: {
-                            y: {
-                                beginAtZero: true,
-                                title: {
-                                    display: true,
-                                    text: 'Memory Usage'
-                                },
-                                ticks: {
-                                    callback: function(value) {
-                                        return formatBytes(value);
-                                    }
-                                }
-                            }
-                        },
-                        plugins: {
-                            tooltip: {
-                                callbacks: {
-                                    label: function(context) {
-                                        var label = context.dataset.label || '';
-                                        if (label) {
-                                            label += ': ';
-                                        }
-                                        label += formatBytes(context.raw);
-                                        return label;
-                                    }
-                                }
-                            }
-                        }
-                    }
-                });
-`)
-	}
-
-	// Close JavaScript and HTML
-	html.WriteString(`
-            });
-        </script>
-    </div>
-</body>
-</html>
-`)
+// chartGroups returns the values to chart for a result, one group per type
+// for multi-type tests
+func chartGroups(r memory.TestResult) []chartGroup {
+	typeResults, ok := r.OtherStats["TypeResults"].(map[string]map[string]interface{})
+	if !ok {
+		optMem, _ := r.OtherStats["OptimizedMemory"].(uint64)
+		unoptMem, _ := r.OtherStats["UnoptimizedMemory"].(uint64)
+		return []chartGroup{{label: r.Name, optimized: optMem, unoptimized: unoptMem}}
+	}
 
-	return &html, nil
+	groups := make([]chartGroup, 0, len(typeResults))
+	for typeName, typeData := range typeResults {
+		optMem, _ := typeData["OptimizedMemory"].(uint64)
+		unoptMem, _ := typeData["UnoptimizedMemory"].(uint64)
+		groups = append(groups, chartGroup{label: typeName, optimized: optMem, unoptimized: unoptMem})
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i].label < groups[j].label })
+	return groups
 }
 
 // exportToGitHubPages saves the visualization results to a GitHub Pages friendly directory structure
-func (h *HTMLVisualizer) exportToGitHubPages(results []memory.TestResult, htmlContent *bytes.Buffer) error {
+func (h *HTMLVisualizer) exportToGitHubPages(results []memory.TestResult) error {
 	// Create base directory for GitHub Pages
-	baseDir := "docs"
+	baseDir := h.pagesDir()
 	if err := os.MkdirAll(baseDir, 0755); err != nil {
 		return fmt.Errorf("failed to create GitHub Pages directory: %w", err)
 	}
 
+	summaries, err := loadSummaries(baseDir)
+	if err != nil {
+		return err
+	}
+
+	// Also create dated copies for historical tracking
+	now := time.Now()
+	dateDir := filepath.Join(baseDir, "history", now.Format("2006-01-02"))
+	if err := os.MkdirAll(dateDir, 0755); err != nil {
+		return fmt.Errorf("failed to create history directory: %w", err)
+	}
+
 	// Generate individual test result files
 	for _, result := range results {
 		// Create sanitized filename from test name
 		filename := sanitizeFilename(result.Name) + ".html"
-		testPath := filepath.Join(baseDir, filename)
 
-		// Save the HTML content to the test-specific file
-		if err := os.WriteFile(testPath, htmlContent.Bytes(), 0644); err != nil {
-			return fmt.Errorf("failed to write test HTML file: %w", err)
+		// Test pages sit next to the index and dated copies two levels below it
+		for _, page := range []struct {
+			path      string
+			indexLink string
+		}{
+			{filepath.Join(baseDir, filename), "index.html"},
+			{filepath.Join(dateDir, filename), "../../index.html"},
+		} {
+			htmlContent, err := h.generateTestPage(result, page.indexLink)
+			if err != nil {
+				return err
+			}
+			if err := os.WriteFile(page.path, htmlContent.Bytes(), 0644); err != nil {
+				return fmt.Errorf("failed to write test HTML file: %w", err)
+			}
 		}
-		fmt.Printf("Test result exported to GitHub Pages: %s\n", testPath)
+		fmt.Printf("Test result exported to GitHub Pages: %s\n", filepath.Join(baseDir, filename))
 
-		// Also create dated copy for historical tracking
-		dateDir := filepath.Join(baseDir, "history", time.Now().Format("2006-01-02"))
-		if err := os.MkdirAll(dateDir, 0755); err != nil {
-			return fmt.Errorf("failed to create history directory: %w", err)
-		}
+		summaries[filename] = summarize(result, now.Format("2006-01-02 15:04:05"))
+	}
 
-		datePath := filepath.Join(dateDir, filename)
-		if err := os.WriteFile(datePath, htmlContent.Bytes(), 0644); err != nil {
-			return fmt.Errorf("failed to write historical HTML file: %w", err)
-		}
+	if err := saveSummaries(baseDir, summaries); err != nil {
+		return err
+	}
+
+	// Store the results in structured form so trends can be computed
+	resultsPath, err := saveRun(dateDir, newStoredRun(results, now))
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Structured results saved to %s\n", resultsPath)
+
+	// Prune the history before the pages built from it are regenerated
+	removed, err := pruneHistory(baseDir, h.Retention)
+	if err != nil {
+		return err
+	}
+	if len(removed) > 0 {
+		fmt.Printf("Pruned %d old history entries (retention %s)\n", len(removed), h.Retention)
+	}
+
+	if err := h.generateTrendsPage(baseDir); err != nil {
+		return fmt.Errorf("failed to generate trends page: %w", err)
 	}
 
 	// Create or update index file that lists all available tests
@@ -514,47 +393,20 @@ func (h *HTMLVisualizer) generateIndexPage(baseDir string) error {
 		historyDirs = []string{}
 	}
 
-	var html bytes.Buffer
-	html.WriteString(`<!DOCTYPE html>
-<html>
-<head>
-    <title>Memory Allocation Test Results - Index</title>
-    <meta charset="UTF-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <style>
-        body { font-family: Arial, sans-serif; margin: 20px; }
-        .container { max-width: 1200px; margin: 0 auto; }
-        h1, h2 { color: #333; }
-        ul { list-style-type: none; padding: 0; }
-        li { margin: 10px 0; padding: 8px; background-color: #f5f5f5; border-radius: 4px; }
-        li:hover { background-color: #e9e9e9; }
-        a { color: #0366d6; text-decoration: none; }
-        a:hover { text-decoration: underline; }
-        .footer { margin-top: 30px; font-size: 0.8em; color: #666; border-top: 1px solid #ddd; padding-top: 10px; }
-        .date { color: #666; font-size: 0.9em; }
-    </style>
-</head>
-<body>
-    <div class="container">
-        <h1>Memory Allocation Test Results</h1>
-        <p>Click on a test to view detailed results.</p>
-        
-        <h2>Latest Test Results</h2>
-        <ul>
-`)
+	summaries, err := loadSummaries(baseDir)
+	if err != nil {
+		return err
+	}
+
+	page := indexPage{Title: "Memory Allocation Test Results"}
 
 	// Add links to all test results
 	for _, file := range files {
-		// Skip index.html itself
-		if filepath.Base(file) == "index.html" {
+		// Skip index.html itself and the trends page
+		if filepath.Base(file) == "index.html" || filepath.Base(file) == trendsFile {
 			continue
 		}
 
-		testName := strings.TrimSuffix(filepath.Base(file), ".html")
-		// Convert filename back to readable title
-		title := strings.ReplaceAll(testName, "_", " ")
-		title = strings.Title(title)
-
 		// Get file modification time
 		fileInfo, err := os.Stat(file)
 		dateStr := ""
@@ -562,39 +414,30 @@ func (h *HTMLVisualizer) generateIndexPage(baseDir string) error {
 			dateStr = fileInfo.ModTime().Format("2006-01-02 15:04:05")
 		}
 
-		html.WriteString(fmt.Sprintf(`        <li><a href="%s">%s</a> <span class="date">%s</span></li>
-`, filepath.Base(file), title, dateStr))
-	}
-
-	// Add history section if we have historical data
-	if len(historyDirs) > 0 {
-		html.WriteString(`
-        <h2>Historical Test Results</h2>
-        <ul>
-`)
-		// Sort in reverse order (newest first)
-		for i := len(historyDirs) - 1; i >= 0; i-- {
-			dir := historyDirs[i]
-			dateStr := filepath.Base(dir)
-			html.WriteString(fmt.Sprintf(`        <li><a href="history/%s/index.html">%s</a></li>
-`, dateStr, dateStr))
-
-			// Create an index file for each history directory
-			h.generateHistoryIndexPage(dir, dateStr)
+		link := pageLink{Href: filepath.Base(file), Title: pageTitle(file), Date: dateStr}
+		if summary, ok := summaries[link.Href]; ok {
+			link.Title = summary.Name
+			link.Summary = &summary
 		}
-		html.WriteString(`        </ul>
-`)
+		page.Tests = append(page.Tests, link)
 	}
 
-	// Close HTML
-	html.WriteString(`
-        <div class="footer">
-            <p>Generated by Memory Allocation Tests - <a href="https://github.com/yourusername/mem-alloc-tests">GitHub Repository</a></p>
-        </div>
-    </div>
-</body>
-</html>
-`)
+	// Sort history in reverse order (newest first)
+	for i := len(historyDirs) - 1; i >= 0; i-- {
+		dir := historyDirs[i]
+		dateStr := filepath.Base(dir)
+		page.History = append(page.History, pageLink{Href: "history/" + dateStr + "/index.html", Title: dateStr})
+
+		// Create an index file for each history directory
+		if err := h.generateHistoryIndexPage(dir, dateStr); err != nil {
+			return err
+		}
+	}
+
+	html, err := h.render(indexTemplate, page)
+	if err != nil {
+		return err
+	}
 
 	// Write index file
 	indexPath := filepath.Join(baseDir, "index.html")
@@ -614,37 +457,7 @@ func (h *HTMLVisualizer) generateHistoryIndexPage(dirPath, dateStr string) error
 		return fmt.Errorf("failed to read historical result files: %w", err)
 	}
 
-	var html bytes.Buffer
-	html.WriteString(fmt.Sprintf(`<!DOCTYPE html>
-<html>
-<head>
-    <title>Memory Allocation Test Results - %s</title>
-    <meta charset="UTF-8">
-    <meta name="viewport" content="width=device-width, initial-scale=1.0">
-    <style>
-        body { font-family: Arial, sans-serif; margin: 20px; }
-        .container { max-width: 1200px; margin: 0 auto; }
-        h1, h2 { color: #333; }
-        ul { list-style-type: none; padding: 0; }
-        li { margin: 10px 0; padding: 8px; background-color: #f5f5f5; border-radius: 4px; }
-        li:hover { background-color: #e9e9e9; }
-        a { color: #0366d6; text-decoration: none; }
-        a:hover { text-decoration: underline; }
-        .footer { margin-top: 30px; font-size: 0.8em; color: #666; border-top: 1px solid #ddd; padding-top: 10px; }
-        .nav-links { margin: 20px 0; }
-        .nav-links a { margin-right: 15px; }
-    </style>
-</head>
-<body>
-    <div class="container">
-        <h1>Memory Allocation Test Results - %s</h1>
-        <div class="nav-links">
-            <a href="../../index.html">Back to Main Index</a>
-        </div>
-        
-        <h2>Test Results</h2>
-        <ul>
-`, dateStr, dateStr))
+	page := indexPage{Title: "Memory Allocation Test Results", Date: dateStr}
 
 	// Add links to all historical test results
 	for _, file := range files {
@@ -652,25 +465,13 @@ func (h *HTMLVisualizer) generateHistoryIndexPage(dirPath, dateStr string) error
 		if filepath.Base(file) == "index.html" {
 			continue
 		}
-
-		testName := strings.TrimSuffix(filepath.Base(file), ".html")
-		// Convert filename back to readable title
-		title := strings.ReplaceAll(testName, "_", " ")
-		title = strings.Title(title)
-
-		html.WriteString(fmt.Sprintf(`        <li><a href="%s">%s</a></li>
-`, filepath.Base(file), title))
+		page.Tests = append(page.Tests, pageLink{Href: filepath.Base(file), Title: pageTitle(file)})
 	}
 
-	// Close HTML
-	html.WriteString(`
-        <div class="footer">
-            <p>Generated by Memory Allocation Tests - <a href="https://github.com/yourusername/mem-alloc-tests">GitHub Repository</a></p>
-        </div>
-    </div>
-</body>
-</html>
-`)
+	html, err := h.render(historyIndexTemplate, page)
+	if err != nil {
+		return err
+	}
 
 	// Write index file
 	indexPath := filepath.Join(dirPath, "index.html")
@@ -681,6 +482,12 @@ func (h *HTMLVisualizer) generateHistoryIndexPage(dirPath, dateStr string) error
 	return nil
 }
 
+// pageTitle converts a result file name back to a readable title
+func pageTitle(file string) string {
+	testName := strings.TrimSuffix(filepath.Base(file), ".html")
+	return strings.Title(strings.ReplaceAll(testName, "_", " "))
+}
+
 // sanitizeFilename converts a test name to a safe filename
 func sanitizeFilename(name string) string {
 	// Convert to lowercase
@@ -704,6 +511,8 @@ func New(format string) Visualizer {
 	switch strings.ToLower(format) {
 	case "stdout", "terminal":
 		return &TerminalVisualizer{}
+	case "tui":
+		return &TUIVisualizer{}
 	case "html", "":
 		// Use HTML as the default if no format is specified
 		return &HTMLVisualizer{
@@ -0,0 +1,323 @@
+package bitpack
+
+import (
+	"fmt"
+	model "mem-tests/model/struct"
+	"mem-tests/pkg/layout"
+	"mem-tests/pkg/memory"
+	"mem-tests/pkg/populate"
+	"mem-tests/tests/fixtures"
+	"reflect"
+	"runtime"
+	"time"
+	"unsafe"
+)
+
+const (
+	// bitpackObjects is the number of records in every population
+	bitpackObjects = 100000
+
+	// accessPasses is the number of times each access loop is repeated when timing it
+	accessPasses = 20
+)
+
+// accessSink keeps the results of flag reads alive so the compiler cannot drop them
+var accessSink int
+
+// flagStats holds the measurements for one variant of a model
+type flagStats struct {
+	// Size is the size of one struct
+	Size uintptr
+
+	// Layout is the layout of the struct
+	Layout layout.Layout
+
+	// Retained is the memory held by the population after a collection
+	Retained uint64
+
+	// ReadNs is the time per record, in nanoseconds, to read every flag
+	ReadNs float64
+
+	// WriteNs is the time per record, in nanoseconds, to write every flag
+	WriteNs float64
+}
+
+// BitPackingTest compares each reordered model against a variant with its
+// bool fields packed into a bitset
+type BitPackingTest struct{}
+
+// Name returns the name of this test
+func (t *BitPackingTest) Name() string {
+	return "Bit-Packing Test"
+}
+
+// flagAccess reads and writes every flag of a struct
+type flagAccess[T any] struct {
+	read  func(*T) int
+	write func(*T, bool)
+}
+
+// bitpackCase measures one model in its packed and reordered variants
+type bitpackCase struct {
+	name      string
+	sample    any
+	packed    func(count int) flagStats
+	reordered func(count int) flagStats
+}
+
+// newBitpackCase builds a bitpackCase from how both variants are built and their flag accessors
+func newBitpackCase[P, R any](name string, sample R,
+	packed func(*fixtures.Values) P, packedFlags flagAccess[P],
+	reordered func(*fixtures.Values) R, reorderedFlags flagAccess[R],
+) bitpackCase {
+	return bitpackCase{
+		name:   name,
+		sample: sample,
+		packed: func(count int) flagStats {
+			return measureFlags(count, fixtures.New(packed), packedFlags)
+		},
+		reordered: func(count int) flagStats {
+			return measureFlags(count, fixtures.New(reordered), reorderedFlags)
+		},
+	}
+}
+
+// Run executes the test and returns results
+func (t *BitPackingTest) Run() memory.TestResult {
+	result := memory.TestResult{
+		Name:       t.Name(),
+		OtherStats: make(map[string]any),
+	}
+
+	empty := populate.Profile{}
+	testCases := []bitpackCase{
+		newBitpackCase("Small Struct", model.OptimizedStruct{},
+			func(v *fixtures.Values) model.SmallPackedStruct { return v.Small(empty).Packed() },
+			flagAccess[model.SmallPackedStruct]{
+				read: func(v *model.SmallPackedStruct) int { return count(v.BoolField(), v.BoolFieldB()) },
+				write: func(v *model.SmallPackedStruct, on bool) {
+					v.SetBoolField(on)
+					v.SetBoolFieldB(on)
+				},
+			},
+			fixtures.Small(empty).Optimized,
+			flagAccess[model.OptimizedStruct]{
+				read: func(v *model.OptimizedStruct) int { return count(v.BoolField, v.BoolFieldB) },
+				write: func(v *model.OptimizedStruct, on bool) {
+					v.BoolField = on
+					v.BoolFieldB = on
+				},
+			}),
+		newBitpackCase("Large Struct", model.LargeOptimizedStruct{},
+			func(v *fixtures.Values) model.LargePackedStruct { return v.Large(empty).Packed() },
+			flagAccess[model.LargePackedStruct]{
+				read: func(v *model.LargePackedStruct) int {
+					return count(v.IsSuccess(), v.IsRetry(), v.IsCached(), v.IsVerified(), v.IsActive(),
+						v.IsAdmin(), v.IsTest(), v.IsPriority(), v.IsFlagged())
+				},
+				write: func(v *model.LargePackedStruct, on bool) {
+					v.SetIsSuccess(on)
+					v.SetIsRetry(on)
+					v.SetIsCached(on)
+					v.SetIsVerified(on)
+					v.SetIsActive(on)
+					v.SetIsAdmin(on)
+					v.SetIsTest(on)
+					v.SetIsPriority(on)
+					v.SetIsFlagged(on)
+				},
+			},
+			fixtures.Large(empty).Optimized,
+			flagAccess[model.LargeOptimizedStruct]{
+				read: func(v *model.LargeOptimizedStruct) int {
+					return count(v.IsSuccess, v.IsRetry, v.IsCached, v.IsVerified, v.IsActive,
+						v.IsAdmin, v.IsTest, v.IsPriority, v.IsFlagged)
+				},
+				write: func(v *model.LargeOptimizedStruct, on bool) {
+					v.IsSuccess = on
+					v.IsRetry = on
+					v.IsCached = on
+					v.IsVerified = on
+					v.IsActive = on
+					v.IsAdmin = on
+					v.IsTest = on
+					v.IsPriority = on
+					v.IsFlagged = on
+				},
+			}),
+		newBitpackCase("API Request", model.APIOptimizedStruct{},
+			func(v *fixtures.Values) model.APIPackedStruct { return v.API().Packed() },
+			flagAccess[model.APIPackedStruct]{
+				read: func(v *model.APIPackedStruct) int { return count(v.Authenticated(), v.Cached()) },
+				write: func(v *model.APIPackedStruct, on bool) {
+					v.SetAuthenticated(on)
+					v.SetCached(on)
+				},
+			},
+			fixtures.API.Optimized,
+			flagAccess[model.APIOptimizedStruct]{
+				read: func(v *model.APIOptimizedStruct) int { return count(v.Authenticated, v.Cached) },
+				write: func(v *model.APIOptimizedStruct, on bool) {
+					v.Authenticated = on
+					v.Cached = on
+				},
+			}),
+		newBitpackCase("Config", model.ConfigOptimizedStruct{},
+			func(v *fixtures.Values) model.ConfigPackedStruct { return v.Config().Packed() },
+			flagAccess[model.ConfigPackedStruct]{
+				read: func(v *model.ConfigPackedStruct) int { return count(v.Debug(), v.Enabled()) },
+				write: func(v *model.ConfigPackedStruct, on bool) {
+					v.SetDebug(on)
+					v.SetEnabled(on)
+				},
+			},
+			fixtures.Config.Optimized,
+			flagAccess[model.ConfigOptimizedStruct]{
+				read: func(v *model.ConfigOptimizedStruct) int { return count(v.Debug, v.Enabled) },
+				write: func(v *model.ConfigOptimizedStruct, on bool) {
+					v.Debug = on
+					v.Enabled = on
+				},
+			}),
+		newBitpackCase("GraphQL", model.GraphQLOptimizedStruct{},
+			func(v *fixtures.Values) model.GraphQLPackedStruct { return v.GraphQL().Packed() },
+			flagAccess[model.GraphQLPackedStruct]{
+				read: func(v *model.GraphQLPackedStruct) int {
+					return count(v.IsMutation(), v.HasVariables(), v.Cached())
+				},
+				write: func(v *model.GraphQLPackedStruct, on bool) {
+					v.SetIsMutation(on)
+					v.SetHasVariables(on)
+					v.SetCached(on)
+				},
+			},
+			fixtures.GraphQL.Optimized,
+			flagAccess[model.GraphQLOptimizedStruct]{
+				read: func(v *model.GraphQLOptimizedStruct) int {
+					return count(v.IsMutation, v.HasVariables, v.Cached)
+				},
+				write: func(v *model.GraphQLOptimizedStruct, on bool) {
+					v.IsMutation = on
+					v.HasVariables = on
+					v.Cached = on
+				},
+			}),
+		newBitpackCase("Database Entity", model.DBEntityOptimizedStruct{},
+			func(v *fixtures.Values) model.DBEntityPackedStruct { return v.DBEntity().Packed() },
+			flagAccess[model.DBEntityPackedStruct]{
+				read: func(v *model.DBEntityPackedStruct) int { return count(v.IsActive(), v.IsAdmin(), v.HasMFA()) },
+				write: func(v *model.DBEntityPackedStruct, on bool) {
+					v.SetIsActive(on)
+					v.SetIsAdmin(on)
+					v.SetHasMFA(on)
+				},
+			},
+			fixtures.DBEntity.Optimized,
+			flagAccess[model.DBEntityOptimizedStruct]{
+				read: func(v *model.DBEntityOptimizedStruct) int { return count(v.IsActive, v.IsAdmin, v.HasMFA) },
+				write: func(v *model.DBEntityOptimizedStruct, on bool) {
+					v.IsActive = on
+					v.IsAdmin = on
+					v.HasMFA = on
+				},
+			}),
+	}
+
+	typeResults := make(map[string]map[string]interface{})
+	var totalSaving uint64
+
+	for _, tc := range testCases {
+		fmt.Printf("\n=== Testing %s Bit-Packing ===\n", tc.name)
+
+		proposal := layout.OfValue(tc.sample).ProposeBitPacking()
+		packed := tc.packed(bitpackObjects)
+		reordered := tc.reordered(bitpackObjects)
+
+		fmt.Printf("%d flags packed into a %s\n", len(proposal.Flags), proposal.BitsetType)
+		printFlagStats("Bit-packed", packed)
+		printFlagStats("Reordered", reordered)
+
+		// The standard keys compare the bit-packed struct against the reordered one
+		typeResult := memory.NewTypeResult(bitpackObjects, packed.Retained, reordered.Retained)
+		typeResult["PackedFlags"] = len(proposal.Flags)
+		typeResult["PackedStructSize"] = packed.Size
+		typeResult["ReorderedStructSize"] = reordered.Size
+		typeResult["PackedReadNs"] = packed.ReadNs
+		typeResult["ReorderedReadNs"] = reordered.ReadNs
+		typeResult["PackedWriteNs"] = packed.WriteNs
+		typeResult["ReorderedWriteNs"] = reordered.WriteNs
+		typeResult["OptimizedLayout"] = packed.Layout
+		typeResult["UnoptimizedLayout"] = reordered.Layout
+
+		typeResults[tc.name] = typeResult
+		totalSaving += typeResult["MemorySaved"].(uint64)
+	}
+
+	result.MemoryUsed = totalSaving
+	result.OtherStats["TypeResults"] = typeResults
+	result.OtherStats["TotalSaving"] = totalSaving
+	result.OtherStats["Variants"] = memory.Variants{Optimized: "Bit-packed", Unoptimized: "Reordered"}
+
+	return result
+}
+
+// measureFlags builds a population and times reading and writing every
+// flag of every record
+func measureFlags[T any](n int, gen fixtures.Generator[T], flags flagAccess[T]) flagStats {
+	runtime.GC()
+	startMem := memory.Usage()
+
+	records := make([]T, n)
+	for i := range records {
+		records[i] = gen(i)
+	}
+
+	runtime.GC()
+	stats := flagStats{
+		Size:     unsafe.Sizeof(records[0]),
+		Layout:   layout.Of(reflect.TypeFor[T]()),
+		Retained: memory.Growth(startMem, memory.Usage()),
+	}
+
+	start := time.Now()
+	for pass := 0; pass < accessPasses; pass++ {
+		for i := range records {
+			accessSink += flags.read(&records[i])
+		}
+	}
+	stats.ReadNs = perRecord(time.Since(start), n)
+
+	start = time.Now()
+	for pass := 0; pass < accessPasses; pass++ {
+		for i := range records {
+			flags.write(&records[i], (i+pass)%2 == 0)
+		}
+	}
+	stats.WriteNs = perRecord(time.Since(start), n)
+	runtime.KeepAlive(records)
+
+	return stats
+}
+
+// count returns the number of flags that are set
+func count(flags ...bool) int {
+	n := 0
+	for _, f := range flags {
+		if f {
+			n++
+		}
+	}
+	return n
+}
+
+// perRecord returns the nanoseconds per record of a timed access loop
+func perRecord(elapsed time.Duration, n int) float64 {
+	return float64(elapsed.Nanoseconds()) / float64(accessPasses*n)
+}
+
+// printFlagStats prints the measurements for one variant
+func printFlagStats(label string, stats flagStats) {
+	fmt.Printf("%s:\n", label)
+	fmt.Printf("  Struct size: %d bytes, retained memory: %s\n", stats.Size, memory.FormatBytes(stats.Retained))
+	fmt.Printf("  Read all flags: %.2f ns/record, write all flags: %.2f ns/record\n", stats.ReadNs, stats.WriteNs)
+}
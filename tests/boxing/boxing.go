@@ -0,0 +1,235 @@
+package boxing
+
+import (
+	"fmt"
+	model "mem-tests/model/struct"
+	"mem-tests/pkg/memory"
+	"mem-tests/pkg/populate"
+	"mem-tests/tests/fixtures"
+	"runtime"
+	"sort"
+)
+
+// boxingObjects is the number of values stored in every container
+const boxingObjects = 100000
+
+// Containers a value can be stored in
+const (
+	containerTyped = "[]T"
+	containerField = "interface field"
+	containerSlice = "[]any"
+)
+
+// boxField is a struct with a single interface field, like
+// model.OptimizedStruct.InterfaceField
+type boxField struct {
+	Value any
+}
+
+// smallPair is an 8-byte struct payload without pointers, which is copied
+// to the heap when boxed since it does not fit in the interface data word
+type smallPair struct {
+	X, Y int32
+}
+
+// boxStats holds the measurements for filling one container
+type boxStats struct {
+	// Retained is the memory held by the container and its values after a collection
+	Retained uint64
+
+	// Mallocs is the number of heap allocations made while storing the values
+	Mallocs uint64
+
+	// Allocated is the bytes allocated while storing the values
+	Allocated uint64
+}
+
+// InterfaceBoxingTest measures storing values in interface{} fields and
+// []any slices, and whether boxing cancels out the savings from field ordering
+type InterfaceBoxingTest struct{}
+
+// Name returns the name of this test
+func (t *InterfaceBoxingTest) Name() string {
+	return "Interface Boxing Test"
+}
+
+// boxCase measures one model type in both field orders and every container
+type boxCase struct {
+	name      string
+	optimFn   func(container string) boxStats
+	unoptimFn func(container string) boxStats
+}
+
+// newBoxCase builds a boxCase that measures both field orders of m
+func newBoxCase[O, U any](name string, m fixtures.Model[O, U]) boxCase {
+	return boxCase{
+		name: name,
+		optimFn: func(container string) boxStats {
+			return measureContainer(container, boxingObjects, fixtures.New(m.Optimized))
+		},
+		unoptimFn: func(container string) boxStats {
+			return measureContainer(container, boxingObjects, fixtures.New(m.Unoptimized))
+		},
+	}
+}
+
+// Run executes the test and returns results
+func (t *InterfaceBoxingTest) Run() memory.TestResult {
+	result := memory.TestResult{
+		Name:       t.Name(),
+		OtherStats: make(map[string]any),
+	}
+
+	empty := populate.Profile{}
+	testCases := []boxCase{
+		newBoxCase("Small Struct", fixtures.Small(empty)),
+		newBoxCase("Large Struct", fixtures.Large(empty)),
+		newBoxCase("API Request", fixtures.API),
+		newBoxCase("Config", fixtures.Config),
+		newBoxCase("GraphQL", fixtures.GraphQL),
+		newBoxCase("Database Entity", fixtures.DBEntity),
+	}
+
+	fmt.Println("\n=== Testing Boxing Cost by Payload ===")
+	result.OtherStats["PayloadCosts"] = measurePayloads()
+
+	typeResults := make(map[string]map[string]interface{})
+	var totalSaving uint64
+
+	for _, tc := range testCases {
+		fmt.Printf("\n=== Testing %s Boxing ===\n", tc.name)
+
+		// Values stored with their concrete type are the baseline for boxing overhead
+		var typedOpt, typedUnopt boxStats
+
+		for _, container := range []string{containerTyped, containerField, containerSlice} {
+			optimized := tc.optimFn(container)
+			unoptimized := tc.unoptimFn(container)
+			if container == containerTyped {
+				typedOpt, typedUnopt = optimized, unoptimized
+			}
+
+			fmt.Printf("\n--- %s (%s) ---\n", tc.name, container)
+			printBoxStats("Optimized", optimized, typedOpt)
+			printBoxStats("Unoptimized", unoptimized, typedUnopt)
+
+			typeResult := memory.NewTypeResult(boxingObjects, optimized.Retained, unoptimized.Retained)
+			typeResult["OptimizedAllocsPerObject"] = perObject(optimized.Mallocs)
+			typeResult["UnoptimizedAllocsPerObject"] = perObject(unoptimized.Mallocs)
+			typeResult["OptimizedExtraAllocs"] = perObject(optimized.Mallocs) - perObject(typedOpt.Mallocs)
+			typeResult["UnoptimizedExtraAllocs"] = perObject(unoptimized.Mallocs) - perObject(typedUnopt.Mallocs)
+			typeResult["OptimizedBoxingOverhead"] = perObject(optimized.Retained) - perObject(typedOpt.Retained)
+			typeResult["UnoptimizedBoxingOverhead"] = perObject(unoptimized.Retained) - perObject(typedUnopt.Retained)
+
+			typeResults[fmt.Sprintf("%s (%s)", tc.name, container)] = typeResult
+			totalSaving += typeResult["MemorySaved"].(uint64)
+
+			// Boxing rounds each value up to an allocator size class, which
+			// can swallow the bytes saved by reordering fields
+			if container != containerTyped {
+				typedSaving := typedUnopt.Retained - min(typedUnopt.Retained, typedOpt.Retained)
+				if typedSaving > 0 && typeResult["MemorySaved"].(uint64) < typedSaving/2 {
+					fmt.Printf("Boxing wipes out most of the field ordering saving (%s of %s kept)\n",
+						memory.FormatBytes(typeResult["MemorySaved"].(uint64)), memory.FormatBytes(typedSaving))
+				}
+			}
+		}
+	}
+
+	result.MemoryUsed = totalSaving
+	result.OtherStats["TypeResults"] = typeResults
+	result.OtherStats["TotalSaving"] = totalSaving
+
+	return result
+}
+
+// measurePayloads measures the cost of boxing scalar, small struct and
+// pointer payloads into a []any
+func measurePayloads() map[string]map[string]interface{} {
+	shared := &model.APIOptimizedStruct{}
+	payloads := map[string]fixtures.Generator[any]{
+		// The runtime keeps preallocated boxes for single-byte values
+		"int (0-255)":  func(i int) any { return i % 256 },
+		"int (>= 256)": func(i int) any { return 256 + i },
+		"small struct": func(i int) any { return smallPair{X: int32(i), Y: int32(i >> 1)} },
+		"pointer":      func(i int) any { return shared },
+	}
+
+	names := make([]string, 0, len(payloads))
+	for name := range payloads {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	costs := make(map[string]map[string]interface{})
+	for _, name := range names {
+		stats := measureContainer(containerSlice, boxingObjects, payloads[name])
+
+		fmt.Printf("%-14s %.2f allocs/assignment, %.2f bytes/assignment, retained %s\n",
+			name, perObject(stats.Mallocs), perObject(stats.Allocated), memory.FormatBytes(stats.Retained))
+
+		costs[name] = map[string]interface{}{
+			"ObjectCount":         boxingObjects,
+			"AllocsPerAssignment": perObject(stats.Mallocs),
+			"BytesPerAssignment":  perObject(stats.Allocated),
+			"RetainedMemory":      stats.Retained,
+		}
+	}
+
+	return costs
+}
+
+// measureContainer stores count generated values in the given container and
+// measures the allocations made by the stores and the memory retained
+func measureContainer[T any](container string, count int, gen fixtures.Generator[T]) boxStats {
+	runtime.GC()
+	startMem := memory.Usage()
+
+	// The container itself is allocated before the snapshot, so that only the
+	// allocations made by storing values are counted
+	var store func(i int)
+	var keep any
+	switch container {
+	case containerTyped:
+		values := make([]T, count)
+		store, keep = func(i int) { values[i] = gen(i) }, values
+	case containerField:
+		values := make([]boxField, count)
+		store, keep = func(i int) { values[i].Value = gen(i) }, values
+	default:
+		values := make([]any, count)
+		store, keep = func(i int) { values[i] = gen(i) }, values
+	}
+
+	start := memory.TakeSnapshot()
+	for i := 0; i < count; i++ {
+		store(i)
+	}
+	delta := memory.TakeSnapshot().Since(start)
+
+	runtime.GC()
+	stats := boxStats{
+		Retained:  memory.Growth(startMem, memory.Usage()),
+		Mallocs:   delta.Mallocs,
+		Allocated: delta.TotalAlloc,
+	}
+	runtime.KeepAlive(keep)
+
+	return stats
+}
+
+// perObject divides a total by the number of objects in each container
+func perObject(total uint64) float64 {
+	return float64(total) / boxingObjects
+}
+
+// printBoxStats prints the measurements for one variant along with its
+// overhead over storing the values with their concrete type
+func printBoxStats(label string, stats, typed boxStats) {
+	fmt.Printf("%s:\n", label)
+	fmt.Printf("  Retained memory: %s (%.2f bytes/object)\n",
+		memory.FormatBytes(stats.Retained), perObject(stats.Retained))
+	fmt.Printf("  Allocations: %.2f per object (%+.2f over []T), boxing overhead: %+.2f bytes/object\n",
+		perObject(stats.Mallocs), perObject(stats.Mallocs)-perObject(typed.Mallocs),
+		perObject(stats.Retained)-perObject(typed.Retained))
+}
@@ -0,0 +1,113 @@
+package fixtures
+
+import (
+	"math/rand"
+	model "mem-tests/model/struct"
+	"mem-tests/pkg/memory"
+	"mem-tests/pkg/populate"
+)
+
+// Generator produces the i-th object of a test population
+type Generator[T any] func(i int) T
+
+// Values draws the values of the objects of a population, one object after
+// another, from a random source seeded with the run seed
+type Values struct {
+	rnd *rand.Rand
+
+	// i is the index of the object being generated
+	i int
+}
+
+// Small draws the values of a small struct
+func (v *Values) Small(profile populate.Profile) SmallValues {
+	return NewSmallValues(v.rnd, profile)
+}
+
+// Large draws the values of a large struct
+func (v *Values) Large(profile populate.Profile) LargeValues {
+	return NewLargeValues(v.rnd, profile)
+}
+
+// API draws the values of an API request struct
+func (v *Values) API() APIValues {
+	return NewAPIValues(v.rnd)
+}
+
+// Config draws the values of a config struct
+func (v *Values) Config() ConfigValues {
+	return NewConfigValues(v.rnd, v.i)
+}
+
+// GraphQL draws the values of a GraphQL query struct
+func (v *Values) GraphQL() GraphQLValues {
+	return NewGraphQLValues(v.rnd, v.i)
+}
+
+// DBEntity draws the values of a database entity struct
+func (v *Values) DBEntity() DBEntityValues {
+	return NewDBEntityValues(v.rnd, v.i)
+}
+
+// CacheStats draws the values of a cache stats struct
+func (v *Values) CacheStats() CacheStatsValues {
+	return NewCacheStatsValues(v.rnd, v.i)
+}
+
+// New returns a generator that builds each object from the values drawn for
+// it. Every generator owns a random source seeded with the run seed, so two
+// generators that draw the same values, for example for the optimized and
+// unoptimized variants of a model, yield identical data.
+func New[T any](build func(*Values) T) Generator[T] {
+	v := &Values{rnd: memory.NewRand()}
+	return func(i int) T {
+		v.i = i
+		return build(v)
+	}
+}
+
+// Model builds both field orders of one model from the same drawn values
+type Model[O, U any] struct {
+	Optimized   func(*Values) O
+	Unoptimized func(*Values) U
+}
+
+// Small builds the small structs with the given population profile
+func Small(profile populate.Profile) Model[model.OptimizedStruct, model.UnoptimizedStruct] {
+	return Model[model.OptimizedStruct, model.UnoptimizedStruct]{
+		Optimized:   func(v *Values) model.OptimizedStruct { return v.Small(profile).Optimized() },
+		Unoptimized: func(v *Values) model.UnoptimizedStruct { return v.Small(profile).Unoptimized() },
+	}
+}
+
+// Large builds the large structs with the given population profile
+func Large(profile populate.Profile) Model[model.LargeOptimizedStruct, model.LargeUnoptimizedStruct] {
+	return Model[model.LargeOptimizedStruct, model.LargeUnoptimizedStruct]{
+		Optimized:   func(v *Values) model.LargeOptimizedStruct { return v.Large(profile).Optimized() },
+		Unoptimized: func(v *Values) model.LargeUnoptimizedStruct { return v.Large(profile).Unoptimized() },
+	}
+}
+
+// API builds the API request structs
+var API = Model[model.APIOptimizedStruct, model.APIUnoptimizedStruct]{
+	Optimized:   func(v *Values) model.APIOptimizedStruct { return v.API().Optimized() },
+	Unoptimized: func(v *Values) model.APIUnoptimizedStruct { return v.API().Unoptimized() },
+}
+
+// Config builds the config structs
+var Config = Model[model.ConfigOptimizedStruct, model.ConfigUnoptimizedStruct]{
+	Optimized:   func(v *Values) model.ConfigOptimizedStruct { return v.Config().Optimized() },
+	Unoptimized: func(v *Values) model.ConfigUnoptimizedStruct { return v.Config().Unoptimized() },
+}
+
+// GraphQL builds the GraphQL query structs
+var GraphQL = Model[model.GraphQLOptimizedStruct, model.GraphQLUnoptimizedStruct]{
+	Optimized:   func(v *Values) model.GraphQLOptimizedStruct { return v.GraphQL().Optimized() },
+	Unoptimized: func(v *Values) model.GraphQLUnoptimizedStruct { return v.GraphQL().Unoptimized() },
+}
+
+// DBEntity builds the database entity structs
+var DBEntity = Model[model.DBEntityOptimizedStruct, model.DBEntityUnoptimizedStruct]{
+	Optimized:   func(v *Values) model.DBEntityOptimizedStruct { return v.DBEntity().Optimized() },
+	Unoptimized: func(v *Values) model.DBEntityUnoptimizedStruct { return v.DBEntity().Unoptimized() },
+}
@@ -0,0 +1,805 @@
+// Package fixtures generates the data that memory tests populate model structs with
+package fixtures
+
+import (
+	"fmt"
+	"math/rand"
+	model "mem-tests/model/struct"
+	"mem-tests/pkg/memory"
+	"mem-tests/pkg/populate"
+	"net"
+	"time"
+)
+
+// The value generators below produce the logical contents of one object.
+// Optimized and unoptimized variants are both built from the same values,
+// so a given seed yields identical data regardless of field order.
+
+// SmallValues holds the populated fields of a small struct
+type SmallValues struct {
+	StringField    string
+	StringFieldB   string
+	SliceField     []int
+	MapField       map[string]int
+	InterfaceField any
+	Int64Field     int64
+	Int64FieldB    int64
+	Int32Field     int32
+	Int32FieldB    int32
+	Int16Field     int16
+	Int16FieldB    int16
+	Int8Field      int8
+	BoolField      bool
+}
+
+// NewSmallValues generates the values of one small struct
+func NewSmallValues(rnd *rand.Rand, profile populate.Profile) SmallValues {
+	return SmallValues{
+		StringField:    profile.Text(rnd),
+		StringFieldB:   profile.Text(rnd),
+		SliceField:     populate.Slice(profile, rnd, func(r *rand.Rand) int { return r.Int() }),
+		MapField:       populate.Map(profile, rnd, profile.Text, func(r *rand.Rand) int { return r.Int() }),
+		InterfaceField: profile.Boxed(rnd),
+		Int64Field:     123456789,
+		Int64FieldB:    987654321,
+		Int32Field:     123456,
+		Int32FieldB:    654321,
+		Int16Field:     1234,
+		Int16FieldB:    4321,
+		Int8Field:      123,
+		BoolField:      true,
+	}
+}
+
+// Optimized builds a model.OptimizedStruct from the values
+func (v SmallValues) Optimized() model.OptimizedStruct {
+	return model.OptimizedStruct{
+		StringField:    v.StringField,
+		StringFieldB:   v.StringFieldB,
+		SliceField:     v.SliceField,
+		MapField:       v.MapField,
+		InterfaceField: v.InterfaceField,
+		Int64Field:     v.Int64Field,
+		Int64FieldB:    v.Int64FieldB,
+		Int32Field:     v.Int32Field,
+		Int32FieldB:    v.Int32FieldB,
+		Int16Field:     v.Int16Field,
+		Int16FieldB:    v.Int16FieldB,
+		Int8Field:      v.Int8Field,
+		BoolField:      v.BoolField,
+	}
+}
+
+// Unoptimized builds a model.UnoptimizedStruct from the values
+func (v SmallValues) Unoptimized() model.UnoptimizedStruct {
+	return model.UnoptimizedStruct{
+		BoolField:      v.BoolField,
+		Int8Field:      v.Int8Field,
+		Int16Field:     v.Int16Field,
+		Int32Field:     v.Int32Field,
+		Int64Field:     v.Int64Field,
+		StringField:    v.StringField,
+		Int16FieldB:    v.Int16FieldB,
+		Int32FieldB:    v.Int32FieldB,
+		InterfaceField: v.InterfaceField,
+		SliceField:     v.SliceField,
+		MapField:       v.MapField,
+		StringFieldB:   v.StringFieldB,
+		Int64FieldB:    v.Int64FieldB,
+	}
+}
+
+// Packed builds a model.SmallPackedStruct from the values
+func (v SmallValues) Packed() model.SmallPackedStruct {
+	packed := model.SmallPackedStruct{
+		StringField:    v.StringField,
+		StringFieldB:   v.StringFieldB,
+		SliceField:     v.SliceField,
+		MapField:       v.MapField,
+		InterfaceField: v.InterfaceField,
+		Int64Field:     v.Int64Field,
+		Int64FieldB:    v.Int64FieldB,
+		Int32Field:     v.Int32Field,
+		Int32FieldB:    v.Int32FieldB,
+		Int16Field:     v.Int16Field,
+		Int16FieldB:    v.Int16FieldB,
+		Int8Field:      v.Int8Field,
+	}
+	packed.SetBoolField(v.BoolField)
+	return packed
+}
+
+// UnixNanos builds a model.SmallUnixNanosStruct from the values
+func (v SmallValues) UnixNanos() model.SmallUnixNanosStruct {
+	return model.SmallUnixNanosStruct{
+		StringField:    v.StringField,
+		StringFieldB:   v.StringFieldB,
+		SliceField:     v.SliceField,
+		MapField:       v.MapField,
+		InterfaceField: v.InterfaceField,
+		Int64Field:     v.Int64Field,
+		Int64FieldB:    v.Int64FieldB,
+		Int32Field:     v.Int32Field,
+		Int32FieldB:    v.Int32FieldB,
+		Int16Field:     v.Int16Field,
+		Int16FieldB:    v.Int16FieldB,
+		Int8Field:      v.Int8Field,
+		BoolField:      v.BoolField,
+	}
+}
+
+// EpochSeconds builds a model.SmallEpochSecondsStruct from the values
+func (v SmallValues) EpochSeconds() model.SmallEpochSecondsStruct {
+	return model.SmallEpochSecondsStruct{
+		StringField:    v.StringField,
+		StringFieldB:   v.StringFieldB,
+		SliceField:     v.SliceField,
+		MapField:       v.MapField,
+		InterfaceField: v.InterfaceField,
+		Int64Field:     v.Int64Field,
+		Int64FieldB:    v.Int64FieldB,
+		Int32Field:     v.Int32Field,
+		Int32FieldB:    v.Int32FieldB,
+		Int16Field:     v.Int16Field,
+		Int16FieldB:    v.Int16FieldB,
+		Int8Field:      v.Int8Field,
+		BoolField:      v.BoolField,
+	}
+}
+
+// LargeValues holds the populated fields of a large struct
+type LargeValues struct {
+	UserProfile       map[string]string
+	ActivityHistory   []time.Time
+	Settings          map[string]bool
+	Tags              []string
+	IPAddress         net.IP
+	Metrics           map[string]float64
+	CreatedAt         time.Time
+	UpdatedAt         time.Time
+	TransactionID     uint64
+	UserID            uint64
+	OrderID           uint64
+	RequestTimestamp  int64
+	ResponseTimestamp int64
+	StatusCode        int32
+	RequestCount      int32
+	RetryAttempts     int32
+	ServiceTime       float32
+	CPUTime           float32
+	ProtocolVersion   uint16
+	ServerRegion      uint16
+	IsSuccess         bool
+	IsCached          bool
+	Priority          uint8
+	CompressionLevel  uint8
+}
+
+// NewLargeValues generates the values of one large struct
+func NewLargeValues(rnd *rand.Rand, profile populate.Profile) LargeValues {
+	now := memory.RandomTime(rnd)
+
+	var ip net.IP
+	if !profile.IsEmpty() {
+		ip = net.IPv4(10, byte(rnd.Intn(256)), byte(rnd.Intn(256)), byte(rnd.Intn(256)))
+	}
+
+	return LargeValues{
+		UserProfile: populate.Map(profile, rnd, profile.Text, profile.Text),
+		ActivityHistory: populate.Slice(profile, rnd, func(r *rand.Rand) time.Time {
+			return now.Add(-time.Duration(r.Intn(86400)) * time.Second)
+		}),
+		Settings:          populate.Map(profile, rnd, profile.Text, func(r *rand.Rand) bool { return r.Intn(2) == 0 }),
+		Tags:              populate.Slice(profile, rnd, profile.Text),
+		IPAddress:         ip,
+		Metrics:           populate.Map(profile, rnd, profile.Text, func(r *rand.Rand) float64 { return r.Float64() * 100 }),
+		CreatedAt:         now.Add(-time.Duration(rnd.Intn(3600)) * time.Second),
+		UpdatedAt:         now,
+		TransactionID:     uint64(rnd.Int63()),
+		UserID:            uint64(rnd.Intn(1000000)),
+		OrderID:           uint64(rnd.Int63()),
+		RequestTimestamp:  now.Add(-time.Duration(rnd.Intn(500)) * time.Millisecond).UnixNano(),
+		ResponseTimestamp: now.UnixNano(),
+		StatusCode:        200,
+		RequestCount:      int32(1 + rnd.Intn(5)),
+		RetryAttempts:     int32(rnd.Intn(3)),
+		ServiceTime:       float32(rnd.Float64() * 100),
+		CPUTime:           float32(rnd.Float64() * 50),
+		ProtocolVersion:   2,
+		ServerRegion:      uint16(rnd.Intn(10)),
+		IsSuccess:         true,
+		IsCached:          rnd.Float32() < 0.3, // 30% cache hit rate
+		Priority:          uint8(rnd.Intn(5)),
+		CompressionLevel:  uint8(rnd.Intn(10)),
+	}
+}
+
+// Optimized builds a model.LargeOptimizedStruct from the values
+func (v LargeValues) Optimized() model.LargeOptimizedStruct {
+	return model.LargeOptimizedStruct{
+		UserProfile:       v.UserProfile,
+		ActivityHistory:   v.ActivityHistory,
+		Settings:          v.Settings,
+		Tags:              v.Tags,
+		IPAddress:         v.IPAddress,
+		Metrics:           v.Metrics,
+		CreatedAt:         v.CreatedAt,
+		UpdatedAt:         v.UpdatedAt,
+		TransactionID:     v.TransactionID,
+		UserID:            v.UserID,
+		OrderID:           v.OrderID,
+		RequestTimestamp:  v.RequestTimestamp,
+		ResponseTimestamp: v.ResponseTimestamp,
+		StatusCode:        v.StatusCode,
+		RequestCount:      v.RequestCount,
+		RetryAttempts:     v.RetryAttempts,
+		ServiceTime:       v.ServiceTime,
+		CPUTime:           v.CPUTime,
+		ProtocolVersion:   v.ProtocolVersion,
+		ServerRegion:      v.ServerRegion,
+		IsSuccess:         v.IsSuccess,
+		IsCached:          v.IsCached,
+		Priority:          v.Priority,
+		CompressionLevel:  v.CompressionLevel,
+	}
+}
+
+// Unoptimized builds a model.LargeUnoptimizedStruct from the values
+func (v LargeValues) Unoptimized() model.LargeUnoptimizedStruct {
+	return model.LargeUnoptimizedStruct{
+		IsSuccess:         v.IsSuccess,
+		Priority:          v.Priority,
+		UserID:            v.UserID,
+		StatusCode:        v.StatusCode,
+		ServiceTime:       v.ServiceTime,
+		CreatedAt:         v.CreatedAt,
+		TransactionID:     v.TransactionID,
+		IsCached:          v.IsCached,
+		ProtocolVersion:   v.ProtocolVersion,
+		ServerRegion:      v.ServerRegion,
+		UserProfile:       v.UserProfile,
+		UpdatedAt:         v.UpdatedAt,
+		OrderID:           v.OrderID,
+		RequestTimestamp:  v.RequestTimestamp,
+		ResponseTimestamp: v.ResponseTimestamp,
+		Tags:              v.Tags,
+		RetryAttempts:     v.RetryAttempts,
+		CPUTime:           v.CPUTime,
+		ActivityHistory:   v.ActivityHistory,
+		CompressionLevel:  v.CompressionLevel,
+		IPAddress:         v.IPAddress,
+		RequestCount:      v.RequestCount,
+		Settings:          v.Settings,
+		Metrics:           v.Metrics,
+	}
+}
+
+// Packed builds a model.LargePackedStruct from the values
+func (v LargeValues) Packed() model.LargePackedStruct {
+	packed := model.LargePackedStruct{
+		UserProfile:       v.UserProfile,
+		ActivityHistory:   v.ActivityHistory,
+		Settings:          v.Settings,
+		Tags:              v.Tags,
+		IPAddress:         v.IPAddress,
+		Metrics:           v.Metrics,
+		CreatedAt:         v.CreatedAt,
+		UpdatedAt:         v.UpdatedAt,
+		TransactionID:     v.TransactionID,
+		UserID:            v.UserID,
+		OrderID:           v.OrderID,
+		RequestTimestamp:  v.RequestTimestamp,
+		ResponseTimestamp: v.ResponseTimestamp,
+		StatusCode:        v.StatusCode,
+		RequestCount:      v.RequestCount,
+		RetryAttempts:     v.RetryAttempts,
+		ServiceTime:       v.ServiceTime,
+		CPUTime:           v.CPUTime,
+		ProtocolVersion:   v.ProtocolVersion,
+		ServerRegion:      v.ServerRegion,
+		Priority:          v.Priority,
+		CompressionLevel:  v.CompressionLevel,
+	}
+	packed.SetIsSuccess(v.IsSuccess)
+	packed.SetIsCached(v.IsCached)
+	return packed
+}
+
+// UnixNanos builds a model.LargeUnixNanosStruct from the values
+func (v LargeValues) UnixNanos() model.LargeUnixNanosStruct {
+	return model.LargeUnixNanosStruct{
+		UserProfile:       v.UserProfile,
+		ActivityHistory:   convertTimes(v.ActivityHistory, model.NewUnixNanos),
+		Settings:          v.Settings,
+		Tags:              v.Tags,
+		IPAddress:         v.IPAddress,
+		Metrics:           v.Metrics,
+		CreatedAt:         model.NewUnixNanos(v.CreatedAt),
+		UpdatedAt:         model.NewUnixNanos(v.UpdatedAt),
+		TransactionID:     v.TransactionID,
+		UserID:            v.UserID,
+		OrderID:           v.OrderID,
+		RequestTimestamp:  v.RequestTimestamp,
+		ResponseTimestamp: v.ResponseTimestamp,
+		StatusCode:        v.StatusCode,
+		RequestCount:      v.RequestCount,
+		RetryAttempts:     v.RetryAttempts,
+		ServiceTime:       v.ServiceTime,
+		CPUTime:           v.CPUTime,
+		ProtocolVersion:   v.ProtocolVersion,
+		ServerRegion:      v.ServerRegion,
+		IsSuccess:         v.IsSuccess,
+		IsCached:          v.IsCached,
+		Priority:          v.Priority,
+		CompressionLevel:  v.CompressionLevel,
+	}
+}
+
+// EpochSeconds builds a model.LargeEpochSecondsStruct from the values
+func (v LargeValues) EpochSeconds() model.LargeEpochSecondsStruct {
+	return model.LargeEpochSecondsStruct{
+		UserProfile:       v.UserProfile,
+		ActivityHistory:   convertTimes(v.ActivityHistory, model.NewEpochSeconds),
+		Settings:          v.Settings,
+		Tags:              v.Tags,
+		IPAddress:         v.IPAddress,
+		Metrics:           v.Metrics,
+		CreatedAt:         model.NewEpochSeconds(v.CreatedAt),
+		UpdatedAt:         model.NewEpochSeconds(v.UpdatedAt),
+		TransactionID:     v.TransactionID,
+		UserID:            v.UserID,
+		OrderID:           v.OrderID,
+		RequestTimestamp:  v.RequestTimestamp,
+		ResponseTimestamp: v.ResponseTimestamp,
+		StatusCode:        v.StatusCode,
+		RequestCount:      v.RequestCount,
+		RetryAttempts:     v.RetryAttempts,
+		ServiceTime:       v.ServiceTime,
+		CPUTime:           v.CPUTime,
+		ProtocolVersion:   v.ProtocolVersion,
+		ServerRegion:      v.ServerRegion,
+		IsSuccess:         v.IsSuccess,
+		IsCached:          v.IsCached,
+		Priority:          v.Priority,
+		CompressionLevel:  v.CompressionLevel,
+	}
+}
+
+// APIValues holds the populated fields of an API request struct
+type APIValues struct {
+	RequestID     uint64
+	UserID        uint64
+	Timestamp     int64
+	SessionID     uint64
+	StatusCode    int32
+	Latency       float32
+	APIVersion    uint16
+	Method        byte
+	Authenticated bool
+	Cached        bool
+}
+
+// NewAPIValues generates the values of one API request struct
+func NewAPIValues(rnd *rand.Rand) APIValues {
+	now := memory.RandomTime(rnd)
+	return APIValues{
+		RequestID:     uint64(rnd.Int63()),
+		UserID:        uint64(rnd.Intn(1000000)),
+		Timestamp:     now.Add(-time.Duration(rnd.Intn(3600)) * time.Second).UnixNano(),
+		SessionID:     uint64(rnd.Int63()),
+		StatusCode:    200,
+		Latency:       float32(rnd.Float64() * 100),
+		APIVersion:    1,
+		Method:        byte('G'), // GET
+		Authenticated: true,
+		Cached:        false,
+	}
+}
+
+// Optimized builds a model.APIOptimizedStruct from the values
+func (v APIValues) Optimized() model.APIOptimizedStruct {
+	return model.APIOptimizedStruct{
+		RequestID:     v.RequestID,
+		UserID:        v.UserID,
+		Timestamp:     v.Timestamp,
+		SessionID:     v.SessionID,
+		StatusCode:    v.StatusCode,
+		Latency:       v.Latency,
+		APIVersion:    v.APIVersion,
+		Method:        v.Method,
+		Authenticated: v.Authenticated,
+		Cached:        v.Cached,
+	}
+}
+
+// Unoptimized builds a model.APIUnoptimizedStruct from the values
+func (v APIValues) Unoptimized() model.APIUnoptimizedStruct {
+	return model.APIUnoptimizedStruct{
+		Method:        v.Method,
+		Authenticated: v.Authenticated,
+		UserID:        v.UserID,
+		Cached:        v.Cached,
+		StatusCode:    v.StatusCode,
+		APIVersion:    v.APIVersion,
+		RequestID:     v.RequestID,
+		Timestamp:     v.Timestamp,
+		SessionID:     v.SessionID,
+		Latency:       v.Latency,
+	}
+}
+
+// Packed builds a model.APIPackedStruct from the values
+func (v APIValues) Packed() model.APIPackedStruct {
+	packed := model.APIPackedStruct{
+		RequestID:  v.RequestID,
+		UserID:     v.UserID,
+		Timestamp:  v.Timestamp,
+		SessionID:  v.SessionID,
+		StatusCode: v.StatusCode,
+		Latency:    v.Latency,
+		APIVersion: v.APIVersion,
+		Method:     v.Method,
+	}
+	packed.SetAuthenticated(v.Authenticated)
+	packed.SetCached(v.Cached)
+	return packed
+}
+
+// ConfigValues holds the populated fields of a config struct
+type ConfigValues struct {
+	Name           string
+	Description    string
+	Environment    string
+	UpdatedAt      int64
+	CreatedAt      int64
+	MaxConnections int32
+	Timeout        int32
+	Port           uint16
+	Debug          bool
+	Enabled        bool
+}
+
+// ConfigEnvironments are the environments config objects cycle through
+var ConfigEnvironments = []string{"dev", "staging", "production"}
+
+// NewConfigValues generates the values of the i-th config struct
+func NewConfigValues(rnd *rand.Rand, i int) ConfigValues {
+	now := memory.RandomTime(rnd)
+	return ConfigValues{
+		Name:           "app-config",
+		Description:    "Main application configuration",
+		Environment:    ConfigEnvironments[i%len(ConfigEnvironments)],
+		UpdatedAt:      now.Unix(),
+		CreatedAt:      now.Add(-time.Duration(rnd.Intn(90*24)) * time.Hour).Unix(),
+		MaxConnections: 100,
+		Timeout:        30,
+		Port:           8080,
+		Debug:          false,
+		Enabled:        true,
+	}
+}
+
+// Optimized builds a model.ConfigOptimizedStruct from the values
+func (v ConfigValues) Optimized() model.ConfigOptimizedStruct {
+	return model.ConfigOptimizedStruct{
+		Name:           v.Name,
+		Description:    v.Description,
+		Environment:    v.Environment,
+		UpdatedAt:      v.UpdatedAt,
+		CreatedAt:      v.CreatedAt,
+		MaxConnections: v.MaxConnections,
+		Timeout:        v.Timeout,
+		Port:           v.Port,
+		Debug:          v.Debug,
+		Enabled:        v.Enabled,
+	}
+}
+
+// Unoptimized builds a model.ConfigUnoptimizedStruct from the values
+func (v ConfigValues) Unoptimized() model.ConfigUnoptimizedStruct {
+	return model.ConfigUnoptimizedStruct{
+		Debug:          v.Debug,
+		Enabled:        v.Enabled,
+		Port:           v.Port,
+		Name:           v.Name,
+		Timeout:        v.Timeout,
+		Environment:    v.Environment,
+		MaxConnections: v.MaxConnections,
+		CreatedAt:      v.CreatedAt,
+		Description:    v.Description,
+		UpdatedAt:      v.UpdatedAt,
+	}
+}
+
+// Packed builds a model.ConfigPackedStruct from the values
+func (v ConfigValues) Packed() model.ConfigPackedStruct {
+	packed := model.ConfigPackedStruct{
+		Name:           v.Name,
+		Description:    v.Description,
+		Environment:    v.Environment,
+		UpdatedAt:      v.UpdatedAt,
+		CreatedAt:      v.CreatedAt,
+		MaxConnections: v.MaxConnections,
+		Timeout:        v.Timeout,
+		Port:           v.Port,
+	}
+	packed.SetDebug(v.Debug)
+	packed.SetEnabled(v.Enabled)
+	return packed
+}
+
+// GraphQLValues holds the populated fields of a GraphQL query struct
+type GraphQLValues struct {
+	QueryID         string
+	Operation       string
+	ClientID        string
+	Timestamp       int64
+	Duration        int64
+	Depth           int32
+	ComplexityScore float32
+	FragmentCount   uint16
+	IsMutation      bool
+	HasVariables    bool
+	Cached          bool
+}
+
+// GraphQLOperations are the operation types GraphQL objects cycle through
+var GraphQLOperations = []string{"query", "mutation", "subscription"}
+
+// NewGraphQLValues generates the values of the i-th GraphQL query struct
+func NewGraphQLValues(rnd *rand.Rand, i int) GraphQLValues {
+	now := memory.RandomTime(rnd)
+	return GraphQLValues{
+		QueryID:         fmt.Sprintf("query-%d", i),
+		Operation:       GraphQLOperations[i%len(GraphQLOperations)],
+		ClientID:        fmt.Sprintf("client-%d", i%1000),
+		Timestamp:       now.Add(-time.Duration(rnd.Intn(3600)) * time.Second).UnixNano(),
+		Duration:        int64(rnd.Intn(1000)),
+		Depth:           int32(rnd.Intn(10) + 1),
+		ComplexityScore: float32(rnd.Float64() * 100),
+		FragmentCount:   uint16(rnd.Intn(5)),
+		IsMutation:      i%3 == 1,
+		HasVariables:    i%2 == 0,
+		Cached:          i%5 == 0,
+	}
+}
+
+// Optimized builds a model.GraphQLOptimizedStruct from the values
+func (v GraphQLValues) Optimized() model.GraphQLOptimizedStruct {
+	return model.GraphQLOptimizedStruct{
+		QueryID:         v.QueryID,
+		Operation:       v.Operation,
+		ClientID:        v.ClientID,
+		Timestamp:       v.Timestamp,
+		Duration:        v.Duration,
+		Depth:           v.Depth,
+		ComplexityScore: v.ComplexityScore,
+		FragmentCount:   v.FragmentCount,
+		IsMutation:      v.IsMutation,
+		HasVariables:    v.HasVariables,
+		Cached:          v.Cached,
+	}
+}
+
+// Unoptimized builds a model.GraphQLUnoptimizedStruct from the values
+func (v GraphQLValues) Unoptimized() model.GraphQLUnoptimizedStruct {
+	return model.GraphQLUnoptimizedStruct{
+		IsMutation:      v.IsMutation,
+		Cached:          v.Cached,
+		Depth:           v.Depth,
+		Operation:       v.Operation,
+		HasVariables:    v.HasVariables,
+		FragmentCount:   v.FragmentCount,
+		Timestamp:       v.Timestamp,
+		QueryID:         v.QueryID,
+		ComplexityScore: v.ComplexityScore,
+		ClientID:        v.ClientID,
+		Duration:        v.Duration,
+	}
+}
+
+// Packed builds a model.GraphQLPackedStruct from the values
+func (v GraphQLValues) Packed() model.GraphQLPackedStruct {
+	packed := model.GraphQLPackedStruct{
+		QueryID:         v.QueryID,
+		Operation:       v.Operation,
+		ClientID:        v.ClientID,
+		Timestamp:       v.Timestamp,
+		Duration:        v.Duration,
+		Depth:           v.Depth,
+		ComplexityScore: v.ComplexityScore,
+		FragmentCount:   v.FragmentCount,
+	}
+	packed.SetIsMutation(v.IsMutation)
+	packed.SetHasVariables(v.HasVariables)
+	packed.SetCached(v.Cached)
+	return packed
+}
+
+// DBEntityValues holds the populated fields of a database entity struct
+type DBEntityValues struct {
+	ID          string
+	Name        string
+	Email       string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+	LastLoginAt time.Time
+	LoginCount  int32
+	Status      int32
+	AccessLevel uint16
+	IsActive    bool
+	IsAdmin     bool
+	HasMFA      bool
+}
+
+// NewDBEntityValues generates the values of the i-th database entity struct
+func NewDBEntityValues(rnd *rand.Rand, i int) DBEntityValues {
+	now := memory.RandomTime(rnd)
+	return DBEntityValues{
+		ID:          fmt.Sprintf("user-%d", i),
+		Name:        fmt.Sprintf("User %d", i),
+		Email:       fmt.Sprintf("user%d@example.com", i),
+		CreatedAt:   now.Add(-time.Duration(rnd.Intn(10000)) * time.Hour),
+		UpdatedAt:   now,
+		LastLoginAt: now.Add(-time.Duration(rnd.Intn(100)) * time.Hour),
+		LoginCount:  int32(rnd.Intn(100)),
+		Status:      int32(rnd.Intn(3)),
+		AccessLevel: uint16(rnd.Intn(5)),
+		IsActive:    true,
+		IsAdmin:     i%50 == 0, // 2% are admins
+		HasMFA:      i%3 == 0,  // 33% have MFA
+	}
+}
+
+// Optimized builds a model.DBEntityOptimizedStruct from the values
+func (v DBEntityValues) Optimized() model.DBEntityOptimizedStruct {
+	return model.DBEntityOptimizedStruct{
+		ID:          v.ID,
+		Name:        v.Name,
+		Email:       v.Email,
+		CreatedAt:   v.CreatedAt,
+		UpdatedAt:   v.UpdatedAt,
+		LastLoginAt: v.LastLoginAt,
+		LoginCount:  v.LoginCount,
+		Status:      v.Status,
+		AccessLevel: v.AccessLevel,
+		IsActive:    v.IsActive,
+		IsAdmin:     v.IsAdmin,
+		HasMFA:      v.HasMFA,
+	}
+}
+
+// Unoptimized builds a model.DBEntityUnoptimizedStruct from the values
+func (v DBEntityValues) Unoptimized() model.DBEntityUnoptimizedStruct {
+	return model.DBEntityUnoptimizedStruct{
+		IsActive:    v.IsActive,
+		IsAdmin:     v.IsAdmin,
+		AccessLevel: v.AccessLevel,
+		Email:       v.Email,
+		Status:      v.Status,
+		LastLoginAt: v.LastLoginAt,
+		HasMFA:      v.HasMFA,
+		ID:          v.ID,
+		Name:        v.Name,
+		LoginCount:  v.LoginCount,
+		CreatedAt:   v.CreatedAt,
+		UpdatedAt:   v.UpdatedAt,
+	}
+}
+
+// Packed builds a model.DBEntityPackedStruct from the values
+func (v DBEntityValues) Packed() model.DBEntityPackedStruct {
+	packed := model.DBEntityPackedStruct{
+		ID:          v.ID,
+		Name:        v.Name,
+		Email:       v.Email,
+		CreatedAt:   v.CreatedAt,
+		UpdatedAt:   v.UpdatedAt,
+		LastLoginAt: v.LastLoginAt,
+		LoginCount:  v.LoginCount,
+		Status:      v.Status,
+		AccessLevel: v.AccessLevel,
+	}
+	packed.SetIsActive(v.IsActive)
+	packed.SetIsAdmin(v.IsAdmin)
+	packed.SetHasMFA(v.HasMFA)
+	return packed
+}
+
+// UnixNanos builds a model.DBEntityUnixNanosStruct from the values
+func (v DBEntityValues) UnixNanos() model.DBEntityUnixNanosStruct {
+	return model.DBEntityUnixNanosStruct{
+		ID:          v.ID,
+		Name:        v.Name,
+		Email:       v.Email,
+		CreatedAt:   model.NewUnixNanos(v.CreatedAt),
+		UpdatedAt:   model.NewUnixNanos(v.UpdatedAt),
+		LastLoginAt: model.NewUnixNanos(v.LastLoginAt),
+		LoginCount:  v.LoginCount,
+		Status:      v.Status,
+		AccessLevel: v.AccessLevel,
+		IsActive:    v.IsActive,
+		IsAdmin:     v.IsAdmin,
+		HasMFA:      v.HasMFA,
+	}
+}
+
+// EpochSeconds builds a model.DBEntityEpochSecondsStruct from the values
+func (v DBEntityValues) EpochSeconds() model.DBEntityEpochSecondsStruct {
+	return model.DBEntityEpochSecondsStruct{
+		ID:          v.ID,
+		Name:        v.Name,
+		Email:       v.Email,
+		CreatedAt:   model.NewEpochSeconds(v.CreatedAt),
+		UpdatedAt:   model.NewEpochSeconds(v.UpdatedAt),
+		LastLoginAt: model.NewEpochSeconds(v.LastLoginAt),
+		LoginCount:  v.LoginCount,
+		Status:      v.Status,
+		AccessLevel: v.AccessLevel,
+		IsActive:    v.IsActive,
+		IsAdmin:     v.IsAdmin,
+		HasMFA:      v.HasMFA,
+	}
+}
+
+// convertTimes converts a slice of timestamps to a compact representation,
+// keeping a nil slice nil
+func convertTimes[T any](times []time.Time, convert func(time.Time) T) []T {
+	if times == nil {
+		return nil
+	}
+	converted := make([]T, len(times))
+	for i, t := range times {
+		converted[i] = convert(t)
+	}
+	return converted
+}
+
+// CacheStatsValues holds the populated fields of a cache stats struct
+type CacheStatsValues struct {
+	Hits     uint64
+	Misses   uint64
+	Keys     []string
+	Name     string
+	Shard    int32
+	Evicting bool
+}
+
+// NewCacheStatsValues generates the values of the i-th cache stats struct
+func NewCacheStatsValues(rnd *rand.Rand, i int) CacheStatsValues {
+	keys := make([]string, rnd.Intn(4))
+	for k := range keys {
+		keys[k] = fmt.Sprintf("key-%d-%d", i, k)
+	}
+	return CacheStatsValues{
+		Hits:     uint64(rnd.Intn(1000000)),
+		Misses:   uint64(rnd.Intn(10000)),
+		Keys:     keys,
+		Name:     fmt.Sprintf("shard-%d", i),
+		Shard:    int32(i % 64),
+		Evicting: i%10 == 0, // 10% are evicting
+	}
+}
+
+// FillOptimized sets the fields of s, a model.CacheStatsOptimizedStruct, to
+// the values. The atomic counters must not be copied, so unlike the other
+// models the struct is filled in place rather than returned.
+func (v CacheStatsValues) FillOptimized(s *model.CacheStatsOptimizedStruct) {
+	s.Hits.Store(v.Hits)
+	s.Misses.Store(v.Misses)
+	s.Keys = v.Keys
+	s.Name = v.Name
+	s.Shard = v.Shard
+	s.Evicting = v.Evicting
+}
+
+// FillUnoptimized sets the fields of s, a model.CacheStatsUnoptimizedStruct,
+// to the values
+func (v CacheStatsValues) FillUnoptimized(s *model.CacheStatsUnoptimizedStruct) {
+	s.Hits.Store(v.Hits)
+	s.Misses.Store(v.Misses)
+	s.Evicting = v.Evicting
+	s.Keys = v.Keys
+	s.Shard = v.Shard
+	s.Name = v.Name
+}
@@ -0,0 +1,375 @@
+package interning
+
+import (
+	"fmt"
+	model "mem-tests/model/struct"
+	"mem-tests/pkg/memory"
+	"mem-tests/tests/fixtures"
+	"runtime"
+	"strings"
+	"unique"
+)
+
+// Number of objects to create for each model
+const (
+	// numConfigObjects matches the config population in the multi-type test
+	numConfigObjects = 10000
+
+	// numGraphQLObjects is the number of GraphQL query objects
+	numGraphQLObjects = 100000
+)
+
+// String representations compared by the test
+const (
+	modePlain    = "plain strings"
+	modeInterned = "interned strings"
+	modeUnique   = "unique.Handle"
+)
+
+// internModes lists the representations in the order they are reported
+var internModes = []string{modePlain, modeInterned, modeUnique}
+
+// modeStats holds the measurements for one string representation
+type modeStats struct {
+	// Optimized and Unoptimized are the memory retained by each population,
+	// including its share of the interning table
+	Optimized   uint64
+	Unoptimized uint64
+
+	// Table is the memory held by the interning table
+	Table uint64
+
+	// Distinct is the number of distinct interned values
+	Distinct int
+}
+
+// internTable is a manual string interning table mapping each value to its canonical copy
+type internTable map[string]string
+
+// intern returns the canonical copy of s, storing s if it is new
+func (t internTable) intern(s string) string {
+	if canonical, ok := t[s]; ok {
+		return canonical
+	}
+	t[s] = s
+	return s
+}
+
+// StringInterningTest compares plain strings, manually interned strings and
+// unique.Handle values for the low-cardinality string fields of the models
+type StringInterningTest struct{}
+
+// Name returns the name of this test
+func (t *StringInterningTest) Name() string {
+	return "String Interning Test"
+}
+
+// Run executes the test and returns results
+func (t *StringInterningTest) Run() memory.TestResult {
+	result := memory.TestResult{
+		Name:       t.Name(),
+		OtherStats: make(map[string]any),
+	}
+
+	testCases := []struct {
+		name        string
+		objectCount int
+		runFn       func(count int) map[string]modeStats
+	}{
+		{
+			name:        "Config",
+			objectCount: numConfigObjects,
+			runFn:       runConfig,
+		},
+		{
+			name:        "GraphQL",
+			objectCount: numGraphQLObjects,
+			runFn:       runGraphQL,
+		},
+	}
+
+	typeResults := make(map[string]map[string]interface{})
+	var totalSaving uint64
+
+	for _, tc := range testCases {
+		fmt.Printf("\n=== Testing %s Structs (%d objects) ===\n", tc.name, tc.objectCount)
+		stats := tc.runFn(tc.objectCount)
+
+		for _, mode := range internModes {
+			s := stats[mode]
+			optPerObject := float64(s.Optimized) / float64(tc.objectCount)
+			unoptPerObject := float64(s.Unoptimized) / float64(tc.objectCount)
+
+			fmt.Printf("\n--- %s (%s) ---\n", tc.name, mode)
+			fmt.Printf("Optimized: %s retained (%.2f bytes/object)\n", memory.FormatBytes(s.Optimized), optPerObject)
+			fmt.Printf("Unoptimized: %s retained (%.2f bytes/object)\n", memory.FormatBytes(s.Unoptimized), unoptPerObject)
+			if mode != modePlain {
+				fmt.Printf("Interning table: %s for %d distinct values\n", memory.FormatBytes(s.Table), s.Distinct)
+			}
+
+			typeResult := memory.NewTypeResult(tc.objectCount, s.Optimized, s.Unoptimized)
+			typeResult["OptimizedPerObject"] = optPerObject
+			typeResult["UnoptimizedPerObject"] = unoptPerObject
+			typeResult["TableOverhead"] = s.Table
+			typeResult["DistinctValues"] = s.Distinct
+
+			typeResults[fmt.Sprintf("%s (%s)", tc.name, mode)] = typeResult
+			totalSaving += typeResult["MemorySaved"].(uint64)
+		}
+	}
+
+	result.MemoryUsed = totalSaving
+	result.OtherStats["TypeResults"] = typeResults
+	result.OtherStats["TotalSaving"] = totalSaving
+
+	return result
+}
+
+// runConfig measures the config structs with every string representation.
+// Name, Description and Environment are interned.
+func runConfig(count int) map[string]modeStats {
+	stats := make(map[string]modeStats)
+	drawConfig := (*fixtures.Values).Config
+
+	// Every object gets its own copy of each string, as if decoded from input
+	plain := func(v fixtures.ConfigValues) fixtures.ConfigValues {
+		return withConfigStrings(v, strings.Clone)
+	}
+	optimGen, unoptimGen := fixtures.New(drawConfig), fixtures.New(drawConfig)
+	stats[modePlain] = modeStats{
+		Optimized: measurePopulation(count, func(i int) model.ConfigOptimizedStruct {
+			return plain(optimGen(i)).Optimized()
+		}),
+		Unoptimized: measurePopulation(count, func(i int) model.ConfigUnoptimizedStruct {
+			return plain(unoptimGen(i)).Unoptimized()
+		}),
+	}
+
+	// Collect the values to intern
+	var distinct []string
+	valuesGen := fixtures.New(drawConfig)
+	for i := 0; i < count; i++ {
+		v := valuesGen(i)
+		distinct = append(distinct, v.Name, v.Description, v.Environment)
+	}
+
+	// Decoded copies are replaced by their canonical copy from the table
+	table := make(internTable)
+	_, distinctCount := buildTable(distinct, func(s string) { table.intern(s) })
+	tableSize := memory.DeepSize(table).Retained
+	interned := func(v fixtures.ConfigValues) fixtures.ConfigValues {
+		return withConfigStrings(v, func(s string) string { return table.intern(strings.Clone(s)) })
+	}
+	optimGen, unoptimGen = fixtures.New(drawConfig), fixtures.New(drawConfig)
+	stats[modeInterned] = modeStats{
+		Optimized: tableSize + measurePopulation(count, func(i int) model.ConfigOptimizedStruct {
+			return interned(optimGen(i)).Optimized()
+		}),
+		Unoptimized: tableSize + measurePopulation(count, func(i int) model.ConfigUnoptimizedStruct {
+			return interned(unoptimGen(i)).Unoptimized()
+		}),
+		Table:    tableSize,
+		Distinct: distinctCount,
+	}
+	runtime.KeepAlive(table)
+
+	// Handles are kept alive while the populations are measured so the
+	// populations reuse the canonical values instead of re-creating them
+	var handles []unique.Handle[string]
+	tableSize, distinctCount = buildTable(distinct, func(s string) { handles = append(handles, unique.Make(s)) })
+	optimGen, unoptimGen = fixtures.New(drawConfig), fixtures.New(drawConfig)
+	stats[modeUnique] = modeStats{
+		Optimized: tableSize + measurePopulation(count, func(i int) model.ConfigHandleOptimizedStruct {
+			v := optimGen(i)
+			return model.ConfigHandleOptimizedStruct{
+				Name:           makeHandle(v.Name),
+				Description:    makeHandle(v.Description),
+				Environment:    makeHandle(v.Environment),
+				UpdatedAt:      v.UpdatedAt,
+				CreatedAt:      v.CreatedAt,
+				MaxConnections: v.MaxConnections,
+				Timeout:        v.Timeout,
+				Port:           v.Port,
+				Debug:          v.Debug,
+				Enabled:        v.Enabled,
+			}
+		}),
+		Unoptimized: tableSize + measurePopulation(count, func(i int) model.ConfigHandleUnoptimizedStruct {
+			v := unoptimGen(i)
+			return model.ConfigHandleUnoptimizedStruct{
+				Debug:          v.Debug,
+				Enabled:        v.Enabled,
+				Port:           v.Port,
+				Name:           makeHandle(v.Name),
+				Timeout:        v.Timeout,
+				Environment:    makeHandle(v.Environment),
+				MaxConnections: v.MaxConnections,
+				CreatedAt:      v.CreatedAt,
+				Description:    makeHandle(v.Description),
+				UpdatedAt:      v.UpdatedAt,
+			}
+		}),
+		Table:    tableSize,
+		Distinct: distinctCount,
+	}
+	runtime.KeepAlive(handles)
+
+	return stats
+}
+
+// runGraphQL measures the GraphQL structs with every string representation.
+// Operation and ClientID are interned, while the unique QueryID stays a plain string.
+func runGraphQL(count int) map[string]modeStats {
+	stats := make(map[string]modeStats)
+	drawGraphQL := (*fixtures.Values).GraphQL
+
+	plain := func(v fixtures.GraphQLValues) fixtures.GraphQLValues {
+		return withGraphQLStrings(v, strings.Clone)
+	}
+	optimGen, unoptimGen := fixtures.New(drawGraphQL), fixtures.New(drawGraphQL)
+	stats[modePlain] = modeStats{
+		Optimized: measurePopulation(count, func(i int) model.GraphQLOptimizedStruct {
+			return plain(optimGen(i)).Optimized()
+		}),
+		Unoptimized: measurePopulation(count, func(i int) model.GraphQLUnoptimizedStruct {
+			return plain(unoptimGen(i)).Unoptimized()
+		}),
+	}
+
+	// Collect the values to intern
+	var distinct []string
+	valuesGen := fixtures.New(drawGraphQL)
+	for i := 0; i < count; i++ {
+		v := valuesGen(i)
+		distinct = append(distinct, v.Operation, v.ClientID)
+	}
+
+	table := make(internTable)
+	_, distinctCount := buildTable(distinct, func(s string) { table.intern(s) })
+	tableSize := memory.DeepSize(table).Retained
+	interned := func(v fixtures.GraphQLValues) fixtures.GraphQLValues {
+		return withGraphQLStrings(v, func(s string) string { return table.intern(strings.Clone(s)) })
+	}
+	optimGen, unoptimGen = fixtures.New(drawGraphQL), fixtures.New(drawGraphQL)
+	stats[modeInterned] = modeStats{
+		Optimized: tableSize + measurePopulation(count, func(i int) model.GraphQLOptimizedStruct {
+			return interned(optimGen(i)).Optimized()
+		}),
+		Unoptimized: tableSize + measurePopulation(count, func(i int) model.GraphQLUnoptimizedStruct {
+			return interned(unoptimGen(i)).Unoptimized()
+		}),
+		Table:    tableSize,
+		Distinct: distinctCount,
+	}
+	runtime.KeepAlive(table)
+
+	var handles []unique.Handle[string]
+	tableSize, distinctCount = buildTable(distinct, func(s string) { handles = append(handles, unique.Make(s)) })
+	optimGen, unoptimGen = fixtures.New(drawGraphQL), fixtures.New(drawGraphQL)
+	stats[modeUnique] = modeStats{
+		Optimized: tableSize + measurePopulation(count, func(i int) model.GraphQLHandleOptimizedStruct {
+			v := optimGen(i)
+			return model.GraphQLHandleOptimizedStruct{
+				QueryID:         strings.Clone(v.QueryID),
+				Operation:       makeHandle(v.Operation),
+				ClientID:        makeHandle(v.ClientID),
+				Timestamp:       v.Timestamp,
+				Duration:        v.Duration,
+				Depth:           v.Depth,
+				ComplexityScore: v.ComplexityScore,
+				FragmentCount:   v.FragmentCount,
+				IsMutation:      v.IsMutation,
+				HasVariables:    v.HasVariables,
+				Cached:          v.Cached,
+			}
+		}),
+		Unoptimized: tableSize + measurePopulation(count, func(i int) model.GraphQLHandleUnoptimizedStruct {
+			v := unoptimGen(i)
+			return model.GraphQLHandleUnoptimizedStruct{
+				IsMutation:      v.IsMutation,
+				Cached:          v.Cached,
+				Depth:           v.Depth,
+				Operation:       makeHandle(v.Operation),
+				HasVariables:    v.HasVariables,
+				FragmentCount:   v.FragmentCount,
+				Timestamp:       v.Timestamp,
+				QueryID:         strings.Clone(v.QueryID),
+				ComplexityScore: v.ComplexityScore,
+				ClientID:        makeHandle(v.ClientID),
+				Duration:        v.Duration,
+			}
+		}),
+		Table:    tableSize,
+		Distinct: distinctCount,
+	}
+	runtime.KeepAlive(handles)
+
+	return stats
+}
+
+// withConfigStrings applies f to the interned string fields of a config
+func withConfigStrings(v fixtures.ConfigValues, f func(string) string) fixtures.ConfigValues {
+	v.Name = f(v.Name)
+	v.Description = f(v.Description)
+	v.Environment = f(v.Environment)
+	return v
+}
+
+// withGraphQLStrings applies f to the interned string fields of a GraphQL query.
+// QueryID is only copied, since every query has its own.
+func withGraphQLStrings(v fixtures.GraphQLValues, f func(string) string) fixtures.GraphQLValues {
+	v.QueryID = strings.Clone(v.QueryID)
+	v.Operation = f(v.Operation)
+	v.ClientID = f(v.ClientID)
+	return v
+}
+
+// makeHandle interns a decoded copy of s with the unique package
+func makeHandle(s string) unique.Handle[string] {
+	return unique.Make(strings.Clone(s))
+}
+
+// buildTable interns the distinct values once and returns the memory the
+// table retains along with the number of distinct values.
+// Small tables fall within measurement noise, so callers that can see the
+// table itself should size it with memory.DeepSize instead.
+func buildTable(values []string, intern func(string)) (uint64, int) {
+	seen := make(map[string]bool)
+	var distinct []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			distinct = append(distinct, v)
+		}
+	}
+
+	runtime.GC()
+	startMem := memory.Usage()
+	for _, v := range distinct {
+		intern(strings.Clone(v))
+	}
+	runtime.GC()
+
+	tableSize := memory.Growth(startMem, memory.Usage())
+	runtime.KeepAlive(distinct)
+
+	return tableSize, len(distinct)
+}
+
+// measurePopulation builds count objects and returns the memory they retain
+// once garbage from building them has been collected
+func measurePopulation[T any](count int, build func(i int) T) uint64 {
+	runtime.GC()
+	startMem := memory.Usage()
+
+	objects := make([]T, count)
+	for i := range objects {
+		objects[i] = build(i)
+	}
+
+	runtime.GC()
+	retained := memory.Growth(startMem, memory.Usage())
+	runtime.KeepAlive(objects)
+
+	return retained
+}
@@ -0,0 +1,245 @@
+package maps
+
+import (
+	"fmt"
+	model "mem-tests/model/struct"
+	"mem-tests/pkg/memory"
+	"mem-tests/tests/fixtures"
+	"reflect"
+	"runtime"
+	"strings"
+)
+
+// mapSizes are the entry counts every map shape is measured at.
+// 896 fills a 1024-slot table to its 7/8 load limit and 897 forces the next
+// growth step, so the pair shows the effect of the load factor.
+var mapSizes = []int{896, 897, 10000, 100000, 1000000}
+
+// growthProbeEntries is how many entries are inserted one at a time to detect growth steps
+const growthProbeEntries = 4096
+
+// GrowthStep records an insertion that made a map allocate new storage
+type GrowthStep struct {
+	// Entries is the number of entries in the map after the insertion
+	Entries int
+
+	// Bytes is the memory allocated by the insertion
+	Bytes uint64
+}
+
+// mapStats holds the measurements for one map
+type mapStats struct {
+	// Retained is the memory still held by the map after a collection
+	Retained uint64
+
+	// Allocated is all memory allocated while filling the map, including
+	// storage discarded when the map grew
+	Allocated uint64
+
+	// Estimated is the storage predicted by the Swiss-table layout model
+	Estimated uint64
+}
+
+// MapOverheadTest measures the memory cost of storing optimized and unoptimized
+// structs in maps, as values, behind pointers and as keys
+type MapOverheadTest struct{}
+
+// Name returns the name of this test
+func (t *MapOverheadTest) Name() string {
+	return "Map Memory Overhead Test"
+}
+
+// mapShape compares one way of storing API request structs in a map
+type mapShape struct {
+	name          string
+	optimFn       func(count int) mapStats
+	unoptimFn     func(count int) mapStats
+	optimGrowth   func(limit int) []GrowthStep
+	unoptimGrowth func(limit int) []GrowthStep
+}
+
+// Run executes the test and returns results
+func (t *MapOverheadTest) Run() memory.TestResult {
+	result := memory.TestResult{
+		Name:       t.Name(),
+		OtherStats: make(map[string]any),
+	}
+
+	optValueSize := uint64(reflect.TypeFor[model.APIOptimizedStruct]().Size())
+	unoptValueSize := uint64(reflect.TypeFor[model.APIUnoptimizedStruct]().Size())
+
+	shapes := []mapShape{
+		{
+			name:          "Values",
+			optimFn:       func(n int) mapStats { return measureMap(n, 0, apiOptimizedValue) },
+			unoptimFn:     func(n int) mapStats { return measureMap(n, 0, apiUnoptimizedValue) },
+			optimGrowth:   func(n int) []GrowthStep { return probeGrowth(n, 0, apiOptimizedValue) },
+			unoptimGrowth: func(n int) []GrowthStep { return probeGrowth(n, 0, apiUnoptimizedValue) },
+		},
+		{
+			name:          "Pointers",
+			optimFn:       func(n int) mapStats { return measureMap(n, optValueSize, apiOptimizedPointer) },
+			unoptimFn:     func(n int) mapStats { return measureMap(n, unoptValueSize, apiUnoptimizedPointer) },
+			optimGrowth:   func(n int) []GrowthStep { return probeGrowth(n, optValueSize, apiOptimizedPointer) },
+			unoptimGrowth: func(n int) []GrowthStep { return probeGrowth(n, unoptValueSize, apiUnoptimizedPointer) },
+		},
+		{
+			name:          "Keys",
+			optimFn:       func(n int) mapStats { return measureMap(n, 0, apiOptimizedKey) },
+			unoptimFn:     func(n int) mapStats { return measureMap(n, 0, apiUnoptimizedKey) },
+			optimGrowth:   func(n int) []GrowthStep { return probeGrowth(n, 0, apiOptimizedKey) },
+			unoptimGrowth: func(n int) []GrowthStep { return probeGrowth(n, 0, apiUnoptimizedKey) },
+		},
+	}
+
+	typeResults := make(map[string]map[string]interface{})
+	growthSteps := make(map[string][]GrowthStep)
+	var totalSaving uint64
+
+	for _, shape := range shapes {
+		fmt.Printf("\n=== Testing API Request Structs as Map %s ===\n", shape.name)
+
+		// Detect growth steps by inserting entries one at a time
+		growthSteps[shape.name+" (Optimized)"] = shape.optimGrowth(growthProbeEntries)
+		memory.CleanupAfterTest()
+		growthSteps[shape.name+" (Unoptimized)"] = shape.unoptimGrowth(growthProbeEntries)
+		memory.CleanupAfterTest()
+		printGrowthSteps(shape.name, growthSteps[shape.name+" (Optimized)"], growthSteps[shape.name+" (Unoptimized)"])
+
+		for _, size := range mapSizes {
+			optimized := shape.optimFn(size)
+			memory.CleanupAfterTest()
+			unoptimized := shape.unoptimFn(size)
+			memory.CleanupAfterTest()
+
+			slots := memory.MapCapacity(size)
+			loadFactor := float64(size) / float64(slots)
+
+			fmt.Printf("\n--- %s, %d entries (%d slots, load factor %.2f) ---\n", shape.name, size, slots, loadFactor)
+			fmt.Printf("Optimized: %s retained (%.2f bytes/entry), %s allocated, %s estimated\n",
+				memory.FormatBytes(optimized.Retained), float64(optimized.Retained)/float64(size),
+				memory.FormatBytes(optimized.Allocated), memory.FormatBytes(optimized.Estimated))
+			fmt.Printf("Unoptimized: %s retained (%.2f bytes/entry), %s allocated, %s estimated\n",
+				memory.FormatBytes(unoptimized.Retained), float64(unoptimized.Retained)/float64(size),
+				memory.FormatBytes(unoptimized.Allocated), memory.FormatBytes(unoptimized.Estimated))
+
+			typeResult := memory.NewTypeResult(size, optimized.Retained, unoptimized.Retained)
+			typeResult["OptimizedBytesPerEntry"] = float64(optimized.Retained) / float64(size)
+			typeResult["UnoptimizedBytesPerEntry"] = float64(unoptimized.Retained) / float64(size)
+			typeResult["OptimizedAllocated"] = optimized.Allocated
+			typeResult["UnoptimizedAllocated"] = unoptimized.Allocated
+			typeResult["OptimizedEstimatedMemory"] = optimized.Estimated
+			typeResult["UnoptimizedEstimatedMemory"] = unoptimized.Estimated
+			typeResult["EstimatedSlots"] = slots
+			typeResult["LoadFactor"] = loadFactor
+
+			typeResults[fmt.Sprintf("%s, %d entries", shape.name, size)] = typeResult
+			totalSaving += typeResult["MemorySaved"].(uint64)
+		}
+	}
+
+	result.MemoryUsed = totalSaving
+	result.OtherStats["TypeResults"] = typeResults
+	result.OtherStats["TotalSaving"] = totalSaving
+	result.OtherStats["GrowthSteps"] = growthSteps
+
+	return result
+}
+
+// Entry builders turn generated values into a map key and value for each shape
+
+func apiOptimizedValue(i int, v fixtures.APIValues) (uint64, model.APIOptimizedStruct) {
+	return uint64(i), v.Optimized()
+}
+
+func apiUnoptimizedValue(i int, v fixtures.APIValues) (uint64, model.APIUnoptimizedStruct) {
+	return uint64(i), v.Unoptimized()
+}
+
+func apiOptimizedPointer(i int, v fixtures.APIValues) (uint64, *model.APIOptimizedStruct) {
+	s := v.Optimized()
+	return uint64(i), &s
+}
+
+func apiUnoptimizedPointer(i int, v fixtures.APIValues) (uint64, *model.APIUnoptimizedStruct) {
+	s := v.Unoptimized()
+	return uint64(i), &s
+}
+
+func apiOptimizedKey(i int, v fixtures.APIValues) (model.APIOptimizedStruct, struct{}) {
+	// RequestID is random, so make it unique to keep every key distinct
+	v.RequestID = uint64(i)
+	return v.Optimized(), struct{}{}
+}
+
+func apiUnoptimizedKey(i int, v fixtures.APIValues) (model.APIUnoptimizedStruct, struct{}) {
+	v.RequestID = uint64(i)
+	return v.Unoptimized(), struct{}{}
+}
+
+// measureMap fills a map without a size hint and measures its memory.
+// valueAlloc is the size of the separate allocation each entry makes, if any.
+func measureMap[K comparable, V any](count int, valueAlloc uint64, entry func(int, fixtures.APIValues) (K, V)) mapStats {
+	gen := fixtures.New((*fixtures.Values).API)
+
+	startMem := memory.Usage()
+	startAlloc := memory.TotalAllocated()
+
+	m := make(map[K]V)
+	for i := 0; i < count; i++ {
+		k, v := entry(i, gen(i))
+		m[k] = v
+	}
+
+	allocated := memory.TotalAllocated() - startAlloc
+
+	// Collect the storage discarded during growth so only live memory remains
+	runtime.GC()
+	retained := memory.Growth(startMem, memory.Usage())
+	runtime.KeepAlive(m)
+
+	return mapStats{
+		Retained:  retained,
+		Allocated: allocated,
+		Estimated: memory.MapStorageSize(reflect.TypeOf(m), count) + uint64(count)*valueAlloc,
+	}
+}
+
+// probeGrowth inserts entries one at a time and records every insertion that
+// allocated more than the entry's own value allocation
+func probeGrowth[K comparable, V any](limit int, valueAlloc uint64, entry func(int, fixtures.APIValues) (K, V)) []GrowthStep {
+	gen := fixtures.New((*fixtures.Values).API)
+
+	var steps []GrowthStep
+	m := make(map[K]V)
+	for i := 0; i < limit; i++ {
+		k, v := entry(i, gen(i))
+
+		before := memory.TotalAllocated()
+		m[k] = v
+		allocated := memory.TotalAllocated() - before
+
+		if allocated > valueAlloc {
+			steps = append(steps, GrowthStep{Entries: len(m), Bytes: allocated})
+		}
+	}
+	runtime.KeepAlive(m)
+
+	return steps
+}
+
+// printGrowthSteps prints the growth steps of both variants of a map shape
+func printGrowthSteps(shape string, optimized, unoptimized []GrowthStep) {
+	fmt.Printf("\nGrowth steps for map %s (first %d entries):\n", shape, growthProbeEntries)
+	fmt.Printf("  Optimized:   %s\n", formatGrowthSteps(optimized))
+	fmt.Printf("  Unoptimized: %s\n", formatGrowthSteps(unoptimized))
+}
+
+// formatGrowthSteps renders growth steps as "entries: bytes" pairs
+func formatGrowthSteps(steps []GrowthStep) string {
+	parts := make([]string, len(steps))
+	for i, step := range steps {
+		parts[i] = fmt.Sprintf("%d: %s", step.Entries, memory.FormatBytes(step.Bytes))
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,117 @@
+package narrowing
+
+import (
+	"fmt"
+	"mem-tests/pkg/layout"
+	"mem-tests/pkg/memory"
+	structs "mem-tests/tests/struct"
+)
+
+// IntegerNarrowingTest records the range of values each integer field holds
+// in the populations built by the field order tests, suggests the narrowest
+// type that fits with some headroom and projects the saving on top of
+// reordering the fields
+type IntegerNarrowingTest struct {
+	// headroom is the growth over the observed values a narrowed type must
+	// leave room for, as a fraction of their magnitude
+	headroom float64
+
+	// small and big are the field order tests whose populations, with
+	// the profiles set on them, are observed
+	small *structs.StructOrderTest
+	big   *structs.StructBigTest
+}
+
+// NewIntegerNarrowingTest creates the test with the default headroom,
+// observing the populations of the given field order tests
+func NewIntegerNarrowingTest(small *structs.StructOrderTest, big *structs.StructBigTest) *IntegerNarrowingTest {
+	return &IntegerNarrowingTest{headroom: layout.DefaultHeadroom, small: small, big: big}
+}
+
+// SetHeadroom sets the growth over the observed values a narrowed type must
+// leave room for, as a fraction of their magnitude
+func (t *IntegerNarrowingTest) SetHeadroom(headroom float64) {
+	t.headroom = headroom
+}
+
+// Name returns the name of this test
+func (t *IntegerNarrowingTest) Name() string {
+	return "Integer Narrowing Test"
+}
+
+// Run executes the test and returns results
+func (t *IntegerNarrowingTest) Run() memory.TestResult {
+	result := memory.TestResult{
+		Name:       t.Name(),
+		OtherStats: make(map[string]any),
+	}
+
+	typeResults := make(map[string]map[string]interface{})
+	var totalSaving uint64
+
+	for _, population := range structs.Populations(t.small, t.big) {
+		fmt.Printf("\n=== Observing %s Values ===\n", population.Name)
+
+		narrowing := population.Observe().Narrowing(t.headroom)
+		memory.CleanupAfterTest()
+		printNarrowing(narrowing)
+
+		// The narrowed size is a projection, so the memory of the whole
+		// population is projected from the struct sizes as well
+		narrowedMem := uint64(narrowing.NarrowedSize) * uint64(population.Count)
+		reorderedMem := uint64(narrowing.ReorderedSize) * uint64(population.Count)
+
+		suggestions := make([]string, 0, len(narrowing.Fields))
+		for _, f := range narrowing.Fields {
+			suggestions = append(suggestions, fmt.Sprintf("%s %s -> %s", f.Field, f.Current, f.Suggested))
+		}
+
+		kept := make([]string, 0, len(narrowing.Kept))
+		for _, f := range narrowing.Kept {
+			kept = append(kept, fmt.Sprintf("%s (%s)", f.Field, f.Reason))
+		}
+
+		typeResult := memory.NewTypeResult(population.Count, narrowedMem, reorderedMem)
+		typeResult["Projected"] = true
+		typeResult["OptimizedStructSize"] = narrowing.NarrowedSize
+		typeResult["UnoptimizedStructSize"] = narrowing.ReorderedSize
+		typeResult["Suggestions"] = suggestions
+		typeResult["Kept"] = kept
+		typeResult["OptimizedLayout"] = narrowing.Narrowed
+		typeResult["UnoptimizedLayout"] = narrowing.Reordered
+
+		typeResults[population.Name] = typeResult
+		totalSaving += typeResult["MemorySaved"].(uint64)
+	}
+
+	result.MemoryUsed = totalSaving
+	result.OtherStats["TypeResults"] = typeResults
+	result.OtherStats["TotalSaving"] = totalSaving
+	result.OtherStats["Headroom"] = t.headroom
+	result.OtherStats["Variants"] = memory.Variants{Optimized: "Narrowed", Unoptimized: "Reordered"}
+
+	return result
+}
+
+// printNarrowing prints the suggested types and the projected sizes
+func printNarrowing(n layout.Narrowing) {
+	if len(n.Fields) == 0 {
+		fmt.Printf("No integer field can be narrowed after %d samples\n", n.Samples)
+	} else {
+		fmt.Printf("Suggested types after %d samples with %.0f%% headroom:\n", n.Samples, n.Headroom*100)
+		for _, f := range n.Fields {
+			fmt.Printf("  %-20s %-8s -> %-8s (observed %s..%s)\n",
+				f.Field, f.Current, f.Suggested, f.Min, f.Max)
+		}
+	}
+	for _, f := range n.Kept {
+		fmt.Printf("  %-20s kept, %s\n", f.Field, f.Reason)
+	}
+
+	fmt.Printf("Reordered size: %d bytes, narrowed and reordered: %d bytes", n.ReorderedSize, n.NarrowedSize)
+	if saving := n.Saving(); saving > 0 {
+		fmt.Printf(" (saves %d bytes per struct)\n", saving)
+	} else {
+		fmt.Println(" (no saving, the freed bytes are taken by alignment padding)")
+	}
+}
@@ -0,0 +1,145 @@
+package pool
+
+import (
+	"fmt"
+	"mem-tests/pkg/memory"
+	"mem-tests/tests/fixtures"
+	"runtime"
+	"runtime/debug"
+	"sync"
+)
+
+const (
+	// churnIterations is the number of request batches handled per variant
+	churnIterations = 200
+
+	// churnBatchSize is the number of requests allocated and discarded per iteration
+	churnBatchSize = 10000
+)
+
+// churnStats holds the measurements for one churn run
+type churnStats struct {
+	memory.Delta
+
+	// Requests is the total number of requests handled
+	Requests int
+}
+
+// Throughput returns the number of requests handled per second
+func (s churnStats) Throughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Requests) / s.Elapsed.Seconds()
+}
+
+// PoolChurnTest models high-volume, short-lived API requests that are
+// allocated and discarded in batches, with and without a sync.Pool
+type PoolChurnTest struct{}
+
+// Name returns the name of this test
+func (t *PoolChurnTest) Name() string {
+	return "sync.Pool Churn Test"
+}
+
+// Run executes the test and returns results
+func (t *PoolChurnTest) Run() memory.TestResult {
+	result := memory.TestResult{
+		Name:       t.Name(),
+		OtherStats: make(map[string]any),
+	}
+
+	// Churn only makes sense with the collector running, so GC is re-enabled
+	// for the duration of the test and disabled again afterwards
+	previous := debug.SetGCPercent(100)
+	defer debug.SetGCPercent(previous)
+
+	fmt.Printf("\n=== Testing API Request Churn (%d iterations of %d requests) ===\n",
+		churnIterations, churnBatchSize)
+
+	typeResults := make(map[string]map[string]interface{})
+	var totalSaving uint64
+
+	for _, pooled := range []bool{false, true} {
+		mode := "new"
+		if pooled {
+			mode = "sync.Pool"
+		}
+
+		optimized := churn(churnIterations, churnBatchSize, pooled, fixtures.New(fixtures.API.Optimized))
+		unoptimized := churn(churnIterations, churnBatchSize, pooled, fixtures.New(fixtures.API.Unoptimized))
+
+		fmt.Printf("\n--- API Request (%s) ---\n", mode)
+		printChurnStats("Optimized", optimized)
+		printChurnStats("Unoptimized", unoptimized)
+
+		typeResult := memory.NewTypeResult(optimized.Requests, optimized.TotalAlloc, unoptimized.TotalAlloc)
+		typeResult["OptimizedMallocs"] = optimized.Mallocs
+		typeResult["UnoptimizedMallocs"] = unoptimized.Mallocs
+		typeResult["OptimizedGCCycles"] = optimized.NumGC
+		typeResult["UnoptimizedGCCycles"] = unoptimized.NumGC
+		typeResult["OptimizedGCPause"] = optimized.PauseTotal.String()
+		typeResult["UnoptimizedGCPause"] = unoptimized.PauseTotal.String()
+		typeResult["OptimizedThroughput"] = optimized.Throughput()
+		typeResult["UnoptimizedThroughput"] = unoptimized.Throughput()
+
+		typeResults[fmt.Sprintf("API Request (%s)", mode)] = typeResult
+		totalSaving += typeResult["MemorySaved"].(uint64)
+	}
+
+	result.MemoryUsed = totalSaving
+	result.OtherStats["TypeResults"] = typeResults
+	result.OtherStats["TotalSaving"] = totalSaving
+	result.OtherStats["Iterations"] = churnIterations
+	result.OtherStats["BatchSize"] = churnBatchSize
+
+	return result
+}
+
+// churn allocates batchSize objects per iteration and discards them at the
+// end of the iteration, either leaving them to the collector or returning
+// them to a sync.Pool for the next iteration to reuse
+func churn[T any](iterations, batchSize int, pooled bool, gen fixtures.Generator[T]) churnStats {
+	pool := sync.Pool{New: func() any { return new(T) }}
+	batch := make([]*T, batchSize)
+
+	runtime.GC()
+	start := memory.TakeSnapshot()
+
+	for iter := 0; iter < iterations; iter++ {
+		for j := range batch {
+			var obj *T
+			if pooled {
+				obj = pool.Get().(*T)
+			} else {
+				obj = new(T)
+			}
+			*obj = gen(iter*batchSize + j)
+			batch[j] = obj
+		}
+
+		for j, obj := range batch {
+			if pooled {
+				pool.Put(obj)
+			}
+			batch[j] = nil
+		}
+	}
+
+	stats := churnStats{
+		Delta:    memory.TakeSnapshot().Since(start),
+		Requests: iterations * batchSize,
+	}
+	runtime.KeepAlive(batch)
+
+	return stats
+}
+
+// printChurnStats prints the measurements for one variant
+func printChurnStats(label string, stats churnStats) {
+	fmt.Printf("%s:\n", label)
+	fmt.Printf("  Total allocated: %s in %d mallocs\n",
+		memory.FormatBytes(stats.TotalAlloc), stats.Mallocs)
+	fmt.Printf("  GC cycles: %d (%s paused)\n", stats.NumGC, stats.PauseTotal)
+	fmt.Printf("  Throughput: %.0f requests/s (%s)\n", stats.Throughput(), stats.Elapsed)
+}
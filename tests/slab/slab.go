@@ -0,0 +1,198 @@
+package slab
+
+import (
+	"fmt"
+	"mem-tests/pkg/alloc"
+	"mem-tests/pkg/memory"
+	"mem-tests/tests/fixtures"
+	"runtime"
+	"time"
+)
+
+// slabObjects is the number of entities in every population
+const slabObjects = 100000
+
+// allocStats holds the measurements for building one population
+type allocStats struct {
+	// Retained is the memory held by the population after a collection
+	Retained uint64
+
+	// HeapObjects is the number of heap objects the population keeps alive
+	HeapObjects uint64
+
+	// ScanBytes is the heap the collector scans for pointers because of the population
+	ScanBytes uint64
+
+	// Elapsed is the time taken to build the population
+	Elapsed time.Duration
+
+	// Count is the number of objects in the population
+	Count int
+}
+
+// Throughput returns the number of objects allocated per second
+func (s allocStats) Throughput() float64 {
+	if s.Elapsed <= 0 {
+		return 0
+	}
+	return float64(s.Count) / s.Elapsed.Seconds()
+}
+
+// SlabAllocatorTest compares individual new(T) allocations, a single
+// make([]T) and the chunked alloc.Slab for a cache of database entities
+type SlabAllocatorTest struct{}
+
+// Name returns the name of this test
+func (t *SlabAllocatorTest) Name() string {
+	return "Slab Allocator Test"
+}
+
+// allocCase builds one population strategy for both field orders
+type allocCase struct {
+	mode      string
+	optimFn   func(count int) any
+	unoptimFn func(count int) any
+}
+
+// Run executes the test and returns results
+func (t *SlabAllocatorTest) Run() memory.TestResult {
+	result := memory.TestResult{
+		Name:       t.Name(),
+		OtherStats: make(map[string]any),
+	}
+
+	optimized, unoptimized := fixtures.DBEntity.Optimized, fixtures.DBEntity.Unoptimized
+	testCases := []allocCase{
+		{
+			mode:      "new",
+			optimFn:   buildNew(fixtures.New(optimized)),
+			unoptimFn: buildNew(fixtures.New(unoptimized)),
+		},
+		{
+			mode:      "make",
+			optimFn:   buildMake(fixtures.New(optimized)),
+			unoptimFn: buildMake(fixtures.New(unoptimized)),
+		},
+		{
+			mode:      "slab",
+			optimFn:   buildSlab(fixtures.New(optimized)),
+			unoptimFn: buildSlab(fixtures.New(unoptimized)),
+		},
+	}
+
+	fmt.Printf("\n=== Testing Database Entity Allocation (%d entities) ===\n", slabObjects)
+
+	typeResults := make(map[string]map[string]interface{})
+	var totalSaving uint64
+
+	for _, tc := range testCases {
+		optimized := measurePopulation(slabObjects, tc.optimFn)
+		unoptimized := measurePopulation(slabObjects, tc.unoptimFn)
+
+		fmt.Printf("\n--- Database Entity (%s) ---\n", tc.mode)
+		printAllocStats("Optimized", optimized)
+		printAllocStats("Unoptimized", unoptimized)
+
+		typeResult := memory.NewTypeResult(slabObjects, optimized.Retained, unoptimized.Retained)
+		typeResult["OptimizedHeapObjects"] = optimized.HeapObjects
+		typeResult["UnoptimizedHeapObjects"] = unoptimized.HeapObjects
+		typeResult["OptimizedScanBytes"] = optimized.ScanBytes
+		typeResult["UnoptimizedScanBytes"] = unoptimized.ScanBytes
+		typeResult["OptimizedThroughput"] = optimized.Throughput()
+		typeResult["UnoptimizedThroughput"] = unoptimized.Throughput()
+
+		typeResults[fmt.Sprintf("Database Entity (%s)", tc.mode)] = typeResult
+		totalSaving += typeResult["MemorySaved"].(uint64)
+	}
+
+	result.MemoryUsed = totalSaving
+	result.OtherStats["TypeResults"] = typeResults
+	result.OtherStats["TotalSaving"] = totalSaving
+	result.OtherStats["ChunkSize"] = alloc.DefaultChunkSize
+
+	return result
+}
+
+// buildNew allocates every object individually and keeps a slice of pointers
+func buildNew[T any](gen fixtures.Generator[T]) func(count int) any {
+	return func(count int) any {
+		objects := make([]*T, count)
+		for i := range objects {
+			obj := new(T)
+			*obj = gen(i)
+			objects[i] = obj
+		}
+		return objects
+	}
+}
+
+// buildMake stores every object in one preallocated slice
+func buildMake[T any](gen fixtures.Generator[T]) func(count int) any {
+	return func(count int) any {
+		objects := make([]T, count)
+		for i := range objects {
+			objects[i] = gen(i)
+		}
+		return objects
+	}
+}
+
+// slabPopulation is a slab together with the handles of its objects
+type slabPopulation[T any] struct {
+	slab    *alloc.Slab[T]
+	handles []alloc.Handle
+}
+
+// buildSlab stores every object in a slab and keeps a slice of handles
+func buildSlab[T any](gen fixtures.Generator[T]) func(count int) any {
+	return func(count int) any {
+		population := slabPopulation[T]{
+			slab:    alloc.NewSlab[T](alloc.DefaultChunkSize),
+			handles: make([]alloc.Handle, count),
+		}
+		for i := range population.handles {
+			h, err := population.slab.Put(gen(i))
+			if err != nil {
+				// The population is far smaller than the handle space
+				panic(err)
+			}
+			population.handles[i] = h
+		}
+		return population
+	}
+}
+
+// measurePopulation builds a population and measures the memory, heap
+// objects and GC scan work it adds once garbage from building it is collected
+func measurePopulation(count int, build func(count int) any) allocStats {
+	runtime.GC()
+	startMem := memory.Usage()
+	startObjects := memory.TakeSnapshot().HeapObjects
+	startScan := memory.HeapScanBytes()
+
+	start := time.Now()
+	population := build(count)
+	elapsed := time.Since(start)
+
+	runtime.GC()
+	stats := allocStats{
+		Retained:    memory.Growth(startMem, memory.Usage()),
+		HeapObjects: memory.Growth(startObjects, memory.TakeSnapshot().HeapObjects),
+		ScanBytes:   memory.Growth(startScan, memory.HeapScanBytes()),
+		Elapsed:     elapsed,
+		Count:       count,
+	}
+	runtime.KeepAlive(population)
+
+	return stats
+}
+
+// printAllocStats prints the measurements for one variant
+func printAllocStats(label string, stats allocStats) {
+	fmt.Printf("%s:\n", label)
+	fmt.Printf("  Retained memory: %s (%.2f bytes/entity)\n",
+		memory.FormatBytes(stats.Retained), float64(stats.Retained)/float64(stats.Count))
+	fmt.Printf("  Heap objects: %d (%.2f per entity), GC scan: %s\n",
+		stats.HeapObjects, float64(stats.HeapObjects)/float64(stats.Count), memory.FormatBytes(stats.ScanBytes))
+	fmt.Printf("  Throughput: %.0f entities/s (%s)\n", stats.Throughput(), stats.Elapsed)
+}
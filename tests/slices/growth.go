@@ -0,0 +1,182 @@
+package slices
+
+import (
+	"fmt"
+	"mem-tests/pkg/memory"
+	"mem-tests/pkg/populate"
+	"mem-tests/tests/fixtures"
+	"runtime"
+	"unsafe"
+)
+
+// sliceObjects is the number of elements appended to every slice
+const sliceObjects = 100000
+
+// sliceStats holds the measurements for building one slice
+type sliceStats struct {
+	// Retained is the memory still held after a collection
+	Retained uint64
+
+	// Allocated is all memory allocated while building the slice
+	Allocated uint64
+
+	// Length and Capacity of the final slice
+	Length   int
+	Capacity int
+
+	// Growths is the number of times append reallocated the backing array
+	Growths int
+
+	// Garbage is the size of all backing arrays discarded during growth
+	Garbage uint64
+
+	// Peak is the most backing-array memory live at once, reached while
+	// append copies the old array into the new one
+	Peak uint64
+
+	// Wasted is the memory in unused capacity at the end
+	Wasted uint64
+}
+
+// SliceGrowthTest compares building slices of each model type with append
+// from zero capacity against appending into a preallocated slice
+type SliceGrowthTest struct{}
+
+// Name returns the name of this test
+func (t *SliceGrowthTest) Name() string {
+	return "Slice Growth Test"
+}
+
+// sliceCase measures one model type in both field orders
+type sliceCase struct {
+	name      string
+	optimFn   func(count int, prealloc bool) sliceStats
+	unoptimFn func(count int, prealloc bool) sliceStats
+}
+
+// newSliceCase builds a sliceCase that measures both field orders of m
+func newSliceCase[O, U any](name string, m fixtures.Model[O, U]) sliceCase {
+	return sliceCase{
+		name: name,
+		optimFn: func(n int, prealloc bool) sliceStats {
+			return measureSlice(n, prealloc, fixtures.New(m.Optimized))
+		},
+		unoptimFn: func(n int, prealloc bool) sliceStats {
+			return measureSlice(n, prealloc, fixtures.New(m.Unoptimized))
+		},
+	}
+}
+
+// Run executes the test and returns results
+func (t *SliceGrowthTest) Run() memory.TestResult {
+	result := memory.TestResult{
+		Name:       t.Name(),
+		OtherStats: make(map[string]any),
+	}
+
+	empty := populate.Profile{}
+	testCases := []sliceCase{
+		newSliceCase("Small Struct", fixtures.Small(empty)),
+		newSliceCase("Large Struct", fixtures.Large(empty)),
+		newSliceCase("API Request", fixtures.API),
+		newSliceCase("Config", fixtures.Config),
+		newSliceCase("GraphQL", fixtures.GraphQL),
+		newSliceCase("Database Entity", fixtures.DBEntity),
+	}
+
+	typeResults := make(map[string]map[string]interface{})
+	var totalSaving uint64
+
+	for _, tc := range testCases {
+		fmt.Printf("\n=== Testing %s Slices ===\n", tc.name)
+
+		for _, prealloc := range []bool{false, true} {
+			mode := "append"
+			if prealloc {
+				mode = "make"
+			}
+
+			optimized := tc.optimFn(sliceObjects, prealloc)
+			memory.CleanupAfterTest()
+			unoptimized := tc.unoptimFn(sliceObjects, prealloc)
+			memory.CleanupAfterTest()
+
+			fmt.Printf("\n--- %s (%s) ---\n", tc.name, mode)
+			printSliceStats("Optimized", optimized)
+			printSliceStats("Unoptimized", unoptimized)
+
+			typeResult := memory.NewTypeResult(sliceObjects, optimized.Retained, unoptimized.Retained)
+			typeResult["OptimizedAllocated"] = optimized.Allocated
+			typeResult["UnoptimizedAllocated"] = unoptimized.Allocated
+			typeResult["OptimizedCapacity"] = optimized.Capacity
+			typeResult["UnoptimizedCapacity"] = unoptimized.Capacity
+			typeResult["OptimizedGrowths"] = optimized.Growths
+			typeResult["UnoptimizedGrowths"] = unoptimized.Growths
+			typeResult["OptimizedGarbage"] = optimized.Garbage
+			typeResult["UnoptimizedGarbage"] = unoptimized.Garbage
+			typeResult["OptimizedPeak"] = optimized.Peak
+			typeResult["UnoptimizedPeak"] = unoptimized.Peak
+			typeResult["OptimizedWasted"] = optimized.Wasted
+			typeResult["UnoptimizedWasted"] = unoptimized.Wasted
+
+			typeResults[fmt.Sprintf("%s (%s)", tc.name, mode)] = typeResult
+			totalSaving += typeResult["MemorySaved"].(uint64)
+		}
+	}
+
+	result.MemoryUsed = totalSaving
+	result.OtherStats["TypeResults"] = typeResults
+	result.OtherStats["TotalSaving"] = totalSaving
+
+	return result
+}
+
+// measureSlice builds a slice of count elements with append, either from zero
+// capacity or into a slice preallocated with make, and tracks every growth
+func measureSlice[T any](count int, prealloc bool, gen fixtures.Generator[T]) sliceStats {
+	elemSize := uint64(unsafe.Sizeof(*new(T)))
+
+	startMem := memory.Usage()
+	startAlloc := memory.TotalAllocated()
+
+	var s []T
+	if prealloc {
+		s = make([]T, 0, count)
+	}
+
+	stats := sliceStats{Peak: uint64(cap(s)) * elemSize}
+	for i := 0; i < count; i++ {
+		oldCap := cap(s)
+		s = append(s, gen(i))
+
+		if cap(s) != oldCap {
+			stats.Growths++
+			stats.Garbage += uint64(oldCap) * elemSize
+			stats.Peak = max(stats.Peak, uint64(oldCap+cap(s))*elemSize)
+		}
+	}
+
+	stats.Allocated = memory.TotalAllocated() - startAlloc
+
+	// Collect the discarded backing arrays so only live memory remains
+	runtime.GC()
+	stats.Retained = memory.Growth(startMem, memory.Usage())
+
+	stats.Length = len(s)
+	stats.Capacity = cap(s)
+	stats.Wasted = uint64(cap(s)-len(s)) * elemSize
+	runtime.KeepAlive(s)
+
+	return stats
+}
+
+// printSliceStats prints the measurements for one variant
+func printSliceStats(label string, stats sliceStats) {
+	fmt.Printf("%s:\n", label)
+	fmt.Printf("  Retained memory: %s, allocated: %s\n",
+		memory.FormatBytes(stats.Retained), memory.FormatBytes(stats.Allocated))
+	fmt.Printf("  Length %d, capacity %d (%d growths), wasted capacity: %s\n",
+		stats.Length, stats.Capacity, stats.Growths, memory.FormatBytes(stats.Wasted))
+	fmt.Printf("  Peak backing memory: %s, garbage from growth: %s\n",
+		memory.FormatBytes(stats.Peak), memory.FormatBytes(stats.Garbage))
+}
@@ -0,0 +1,282 @@
+package soa
+
+import (
+	"fmt"
+	model "mem-tests/model/struct"
+	"mem-tests/pkg/layout"
+	"mem-tests/pkg/memory"
+	"mem-tests/pkg/populate"
+	"mem-tests/tests/fixtures"
+	"reflect"
+	"runtime"
+	"time"
+)
+
+const (
+	// soaObjects is the number of records in every population
+	soaObjects = 100000
+
+	// scanPasses is the number of times each scan is repeated when timing it
+	scanPasses = 20
+)
+
+// number is a field type that can be summed by a single-field scan
+type number interface {
+	~int32 | ~int64 | ~uint64 | ~float32 | ~float64
+}
+
+// columns is implemented by the struct-of-arrays models
+type columns[T any] interface {
+	Append(v T)
+	At(i int) T
+	Len() int
+}
+
+// scanSink keeps the results of scans alive so the compiler cannot drop them
+var scanSink float64
+
+// layoutStats holds the measurements for one layout of a population
+type layoutStats struct {
+	// Layout is the layout of one record, set for the arrays of structs
+	Layout *layout.Layout
+
+	// Retained is the memory held by the population after a collection
+	Retained uint64
+
+	// Mallocs is the number of allocations made while building the population
+	Mallocs uint64
+
+	// FieldScan is the time per record, in nanoseconds, to sum a single field
+	FieldScan float64
+
+	// RecordScan is the time per record, in nanoseconds, to read whole records
+	RecordScan float64
+}
+
+// ArrayOfStructsTest compares storing each model as a slice of structs,
+// padded or reordered, against a struct of arrays with one slice per field
+type ArrayOfStructsTest struct{}
+
+// Name returns the name of this test
+func (t *ArrayOfStructsTest) Name() string {
+	return "Array of Structs vs Struct of Arrays Test"
+}
+
+// soaCase measures one model in every layout
+type soaCase struct {
+	name    string
+	field   string
+	measure func(count int) (reordered, padded, soa layoutStats)
+}
+
+// newSoACase builds a soaCase that scans the given field in every layout
+func newSoACase[O, U any, S columns[O], F number](
+	name, field string,
+	m fixtures.Model[O, U],
+	newColumns func(capacity int) S,
+	optimizedField func(*O) *F,
+	unoptimizedField func(*U) *F,
+	column func(S, int) *F,
+) soaCase {
+	return soaCase{
+		name:  name,
+		field: field,
+		measure: func(count int) (layoutStats, layoutStats, layoutStats) {
+			reordered := measureAoS(count, fixtures.New(m.Optimized), optimizedField)
+			padded := measureAoS(count, fixtures.New(m.Unoptimized), unoptimizedField)
+			soa := measureSoA(count, fixtures.New(m.Optimized), newColumns, column)
+			return reordered, padded, soa
+		},
+	}
+}
+
+// Run executes the test and returns results
+func (t *ArrayOfStructsTest) Run() memory.TestResult {
+	result := memory.TestResult{
+		Name:       t.Name(),
+		OtherStats: make(map[string]any),
+	}
+
+	empty := populate.Profile{}
+	testCases := []soaCase{
+		newSoACase("Small Struct", "Int64Field",
+			fixtures.Small(empty),
+			model.NewSmallStructSoA,
+			func(v *model.OptimizedStruct) *int64 { return &v.Int64Field },
+			func(v *model.UnoptimizedStruct) *int64 { return &v.Int64Field },
+			func(s *model.SmallStructSoA, i int) *int64 { return &s.Int64Field[i] }),
+		newSoACase("Large Struct", "Balance",
+			fixtures.Large(empty),
+			model.NewLargeStructSoA,
+			func(v *model.LargeOptimizedStruct) *float64 { return &v.Balance },
+			func(v *model.LargeUnoptimizedStruct) *float64 { return &v.Balance },
+			func(s *model.LargeStructSoA, i int) *float64 { return &s.Balance[i] }),
+		newSoACase("API Request", "Latency",
+			fixtures.API,
+			model.NewAPIStructSoA,
+			func(v *model.APIOptimizedStruct) *float32 { return &v.Latency },
+			func(v *model.APIUnoptimizedStruct) *float32 { return &v.Latency },
+			func(s *model.APIStructSoA, i int) *float32 { return &s.Latency[i] }),
+		newSoACase("Config", "Timeout",
+			fixtures.Config,
+			model.NewConfigStructSoA,
+			func(v *model.ConfigOptimizedStruct) *int32 { return &v.Timeout },
+			func(v *model.ConfigUnoptimizedStruct) *int32 { return &v.Timeout },
+			func(s *model.ConfigStructSoA, i int) *int32 { return &s.Timeout[i] }),
+		newSoACase("GraphQL", "Duration",
+			fixtures.GraphQL,
+			model.NewGraphQLStructSoA,
+			func(v *model.GraphQLOptimizedStruct) *int64 { return &v.Duration },
+			func(v *model.GraphQLUnoptimizedStruct) *int64 { return &v.Duration },
+			func(s *model.GraphQLStructSoA, i int) *int64 { return &s.Duration[i] }),
+		newSoACase("Database Entity", "LoginCount",
+			fixtures.DBEntity,
+			model.NewDBEntityStructSoA,
+			func(v *model.DBEntityOptimizedStruct) *int32 { return &v.LoginCount },
+			func(v *model.DBEntityUnoptimizedStruct) *int32 { return &v.LoginCount },
+			func(s *model.DBEntityStructSoA, i int) *int32 { return &s.LoginCount[i] }),
+	}
+
+	typeResults := make(map[string]map[string]interface{})
+	var totalSaving uint64
+
+	for _, tc := range testCases {
+		fmt.Printf("\n=== Testing %s Layouts (%d records, scanning %s) ===\n", tc.name, soaObjects, tc.field)
+
+		reordered, padded, soa := tc.measure(soaObjects)
+		printLayoutStats("Padded (array of structs)", padded)
+		printLayoutStats("Reordered (array of structs)", reordered)
+		printLayoutStats("Struct of arrays", soa)
+
+		// Both techniques are measured against the padded array of structs
+		for _, technique := range []struct {
+			mode  string
+			stats layoutStats
+		}{
+			{"reordered", reordered},
+			{"SoA", soa},
+		} {
+			typeResult := memory.NewTypeResult(soaObjects, technique.stats.Retained, padded.Retained)
+			typeResult["ScannedField"] = tc.field
+			typeResult["OptimizedAllocs"] = technique.stats.Mallocs
+			typeResult["UnoptimizedAllocs"] = padded.Mallocs
+			typeResult["OptimizedFieldScanNs"] = technique.stats.FieldScan
+			typeResult["UnoptimizedFieldScanNs"] = padded.FieldScan
+			typeResult["OptimizedRecordScanNs"] = technique.stats.RecordScan
+			typeResult["UnoptimizedRecordScanNs"] = padded.RecordScan
+
+			// A struct of arrays has no record layout, so only the padded
+			// record is drawn for it
+			if technique.stats.Layout != nil {
+				typeResult["OptimizedLayout"] = *technique.stats.Layout
+			}
+			typeResult["UnoptimizedLayout"] = *padded.Layout
+
+			typeResults[fmt.Sprintf("%s (%s)", tc.name, technique.mode)] = typeResult
+			totalSaving += typeResult["MemorySaved"].(uint64)
+		}
+	}
+
+	result.MemoryUsed = totalSaving
+	result.OtherStats["TypeResults"] = typeResults
+	result.OtherStats["TotalSaving"] = totalSaving
+	result.OtherStats["ScanPasses"] = scanPasses
+	result.OtherStats["Variants"] = memory.Variants{Optimized: "Rearranged", Unoptimized: "Padded"}
+
+	return result
+}
+
+// measureAoS builds a slice of structs and times scanning one field and
+// copying out whole records. Like measureSoA, the field scan calls its
+// accessor once per record, so the two differ only in the memory they stride.
+func measureAoS[T any, F number](count int, gen fixtures.Generator[T], field func(*T) *F) layoutStats {
+	runtime.GC()
+	startMem := memory.Usage()
+	start := memory.TakeSnapshot()
+
+	records := make([]T, 0, count)
+	for i := 0; i < count; i++ {
+		records = append(records, gen(i))
+	}
+
+	stats := layoutStats{Mallocs: memory.TakeSnapshot().Since(start).Mallocs}
+	runtime.GC()
+	stats.Retained = memory.Growth(startMem, memory.Usage())
+
+	l := layout.Of(reflect.TypeFor[T]())
+	stats.Layout = &l
+
+	stats.FieldScan = timeScan(count, func() float64 {
+		var total float64
+		for i := range records {
+			total += float64(*field(&records[i]))
+		}
+		return total
+	})
+
+	dst := make([]T, count)
+	stats.RecordScan = timeScan(count, func() float64 {
+		for i := range records {
+			dst[i] = records[i]
+		}
+		return 0
+	})
+	runtime.KeepAlive(records)
+
+	return stats
+}
+
+// measureSoA builds a struct of arrays and times scanning one column and
+// reassembling whole records. The column is read through its accessor once
+// per record, the same way measureAoS reads the field.
+func measureSoA[T any, S columns[T], F number](count int, gen fixtures.Generator[T], newColumns func(int) S, column func(S, int) *F) layoutStats {
+	runtime.GC()
+	startMem := memory.Usage()
+	start := memory.TakeSnapshot()
+
+	records := newColumns(count)
+	for i := 0; i < count; i++ {
+		records.Append(gen(i))
+	}
+
+	stats := layoutStats{Mallocs: memory.TakeSnapshot().Since(start).Mallocs}
+	runtime.GC()
+	stats.Retained = memory.Growth(startMem, memory.Usage())
+
+	stats.FieldScan = timeScan(count, func() float64 {
+		var total float64
+		for i := 0; i < count; i++ {
+			total += float64(*column(records, i))
+		}
+		return total
+	})
+
+	dst := make([]T, count)
+	stats.RecordScan = timeScan(count, func() float64 {
+		for i := range dst {
+			dst[i] = records.At(i)
+		}
+		return 0
+	})
+	runtime.KeepAlive(records)
+
+	return stats
+}
+
+// timeScan runs a scan scanPasses times and returns the nanoseconds per record
+func timeScan(count int, scan func() float64) float64 {
+	start := time.Now()
+	for pass := 0; pass < scanPasses; pass++ {
+		scanSink += scan()
+	}
+	return float64(time.Since(start).Nanoseconds()) / float64(scanPasses*count)
+}
+
+// printLayoutStats prints the measurements for one layout
+func printLayoutStats(label string, stats layoutStats) {
+	fmt.Printf("%s:\n", label)
+	fmt.Printf("  Retained memory: %s (%.2f bytes/record), %d allocations\n",
+		memory.FormatBytes(stats.Retained), float64(stats.Retained)/soaObjects, stats.Mallocs)
+	fmt.Printf("  Field scan: %.2f ns/record, whole-record scan: %.2f ns/record\n",
+		stats.FieldScan, stats.RecordScan)
+}
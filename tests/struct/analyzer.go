@@ -2,38 +2,148 @@ package structs
 
 import (
 	"fmt"
+	"mem-tests/pkg/atomics"
+	"mem-tests/pkg/layout"
 	"mem-tests/pkg/memory"
+	"strings"
 )
 
 // StructAnalyzer provides utility functions for analyzing struct memory usage
 type StructAnalyzer struct{}
 
-// CalculateMemorySavings computes memory savings statistics between optimized and unoptimized versions
+// atomicScan is the result of scanning one package's source for atomic access
+type atomicScan struct {
+	report *atomics.Report
+	err    error
+}
+
+// atomicScans caches the scan of each package, including failed ones
+var atomicScans = make(map[string]atomicScan)
+
+// Atomic alignment states recorded in test results
+const (
+	atomicNotChecked = "not checked"
+	atomicMisaligned = "misaligned"
+	atomicRejected   = "reorder rejected"
+	atomicAligned    = "aligned"
+)
+
+// AtomicAlignment is the outcome of checking the fields a struct accesses
+// with sync/atomic on every 32-bit architecture
+type AtomicAlignment struct {
+	// Checked is false when the struct's package could not be scanned, in
+	// which case nothing is known about its atomic fields
+	Checked bool
+
+	// Fields are the fields accessed with sync/atomic
+	Fields []string
+
+	// Checks holds the check for each 32-bit architecture
+	Checks []layout.AtomicCheck
+}
+
+// Rejected reports whether any check rejected the optimal order
+func (a AtomicAlignment) Rejected() bool {
+	for _, check := range a.Checks {
+		if check.ReorderRejected() {
+			return true
+		}
+	}
+	return false
+}
+
+// Misaligned reports whether an atomic field is misaligned on any 32-bit
+// architecture as declared, in the struct or in the elements of a slice
+func (a AtomicAlignment) Misaligned() bool {
+	for _, check := range a.Checks {
+		if check.Err() != nil {
+			return true
+		}
+	}
+	return false
+}
+
+// Status describes the outcome for the test results
+func (a AtomicAlignment) Status() string {
+	switch {
+	case !a.Checked:
+		return atomicNotChecked
+	case a.Misaligned():
+		return atomicMisaligned
+	case a.Rejected():
+		return atomicRejected
+	}
+	return atomicAligned
+}
+
+// Record stores the outcome in a test's stats. AtomicReorderRejected is only
+// set when the struct was checked, so a failed scan does not read as safe.
+func (a AtomicAlignment) Record(stats map[string]interface{}) {
+	stats["AtomicAlignment"] = a.Status()
+	if a.Checked {
+		stats["AtomicReorderRejected"] = a.Rejected()
+	}
+}
+
+// CalculateMemorySavings computes memory savings statistics between optimized and unoptimized versions.
+// The saving is zero, not a wrapped difference, when the optimized version uses more memory,
+// while the saving percentage turns negative.
 func (a *StructAnalyzer) CalculateMemorySavings(result *memory.TestResult, optimizedMem, unoptimizedMem uint64, optimizedSize, unoptimizedSize uintptr) {
-	result.MemoryUsed = unoptimizedMem - optimizedMem
+	result.MemoryUsed = memory.Growth(optimizedMem, unoptimizedMem)
 	result.OtherStats["OptimizedMemory"] = optimizedMem
 	result.OtherStats["UnoptimizedMemory"] = unoptimizedMem
 	result.PerObjectSize = float64(result.MemoryUsed) / float64(numObjects)
 
-	if unoptimizedMem > optimizedMem {
-		result.OtherStats["MemorySavingPercent"] = float64(unoptimizedMem-optimizedMem) / float64(unoptimizedMem) * 100
-	} else {
-		result.OtherStats["MemorySavingPercent"] = 0.0
+	// Keep the sign of the percentage, as NewTypeResult does, so a regression shows
+	var savingPct float64
+	if unoptimizedMem > 0 {
+		savingPct = (float64(unoptimizedMem) - float64(optimizedMem)) / float64(unoptimizedMem) * 100
 	}
+	result.OtherStats["MemorySavingPercent"] = savingPct
 
 	result.OtherStats["OptimizedStructSize"] = optimizedSize
 	result.OtherStats["UnoptimizedStructSize"] = unoptimizedSize
 }
 
-// AnalyzeStructLayout provides a generic function to analyze any struct layout
-func (a *StructAnalyzer) AnalyzeStructLayout(testName string, optimizedSize, unoptimizedSize uintptr, objectCount int) {
+// RecordRetainedSizes stores the shallow and retained size of a sample object of each variant.
+// The retained size includes strings, slices, maps and other data the struct references.
+func (a *StructAnalyzer) RecordRetainedSizes(result *memory.TestResult, optimized, unoptimized memory.RetainedSize) {
+	result.OtherStats["OptimizedRetainedSize"] = optimized.Retained
+	result.OtherStats["UnoptimizedRetainedSize"] = unoptimized.Retained
+
+	fmt.Println("\n=== Retained Size per Object ===")
+	fmt.Printf("Optimized: %d bytes shallow, %d bytes retained\n", optimized.Shallow, optimized.Retained)
+	fmt.Printf("Unoptimized: %d bytes shallow, %d bytes retained\n", unoptimized.Shallow, unoptimized.Retained)
+}
+
+// AnalyzeStructLayout compares the sizes of the optimized and unoptimized
+// variants of a struct. If the optimized order would misalign a field the
+// struct accesses with sync/atomic on a 32-bit platform, that order is
+// rejected and the saving is computed for the order that keeps the atomic
+// fields first instead.
+func (a *StructAnalyzer) AnalyzeStructLayout(testName string, optimized, unoptimized any, objectCount int) {
+	optimizedSize := layout.OfValue(optimized).Size
+	unoptimizedSize := layout.OfValue(unoptimized).Size
+
 	fmt.Printf("\n=== %s Layout Analysis ===\n", testName)
 	fmt.Printf("Optimized struct size: %d bytes\n", optimizedSize)
 	fmt.Printf("Unoptimized struct size: %d bytes\n", unoptimizedSize)
 
+	if alignment, err := atomicAlignment(layout.OfValue(optimized)); err == nil {
+		for _, check := range alignment.Checks {
+			if len(check.Misaligned) == 0 {
+				continue
+			}
+			fmt.Printf("Optimized order rejected: it misaligns %s on %s; keeping %s first takes %d bytes\n",
+				check.Misaligned[0].Name, check.Arch, strings.Join(check.Fields, ", "), check.SafeSize)
+			optimizedSize = check.SafeSize
+			break
+		}
+	}
+
 	// Calculate theoretical memory difference for all objects
-	sizeDiff := unoptimizedSize - optimizedSize
-	if sizeDiff > 0 {
+	if unoptimizedSize > optimizedSize {
+		sizeDiff := unoptimizedSize - optimizedSize
 		fmt.Printf("\nTheoretical memory waste per struct: %d bytes\n", sizeDiff)
 		fmt.Printf("Theoretical total memory waste for %d objects: %d bytes (%.2f MB)\n",
 			objectCount,
@@ -57,3 +167,174 @@ func (a *StructAnalyzer) PrintMemoryStats(memUsed uint64, objectCount int, isMB
 		perObjectBytes,
 		memory.FormatBytes(uint64(perObjectBytes)))
 }
+
+// ProposeBitPacking finds the bool fields of a struct that could be packed
+// into a bitset and prints the proposal along with the size it would save.
+// The sizes keep fields accessed with sync/atomic first when reordering them
+// would misalign them.
+func (a *StructAnalyzer) ProposeBitPacking(v any) layout.BitPacking {
+	l := safeLayout(v)
+	proposal := l.ProposeBitPacking()
+
+	fmt.Printf("\n=== %s Bit-Packing Analysis ===\n", l.Name())
+	printPinned(l)
+	if len(proposal.Flags) == 0 {
+		fmt.Println("No bool fields to pack")
+		return proposal
+	}
+
+	fmt.Printf("Bool fields: %s (%d bytes)\n", strings.Join(proposal.Flags, ", "), proposal.FlagBytes())
+	for _, cluster := range proposal.Clusters {
+		fmt.Printf("  Adjacent cluster: %s\n", strings.Join(cluster, ", "))
+	}
+	if len(proposal.SmallFlags) > 0 {
+		fmt.Printf("Other one-byte fields that could share the bitset: %s\n", strings.Join(proposal.SmallFlags, ", "))
+	}
+
+	fmt.Printf("Proposal: pack %d flags into a %s bitset\n", len(proposal.Flags), proposal.BitsetType)
+	fmt.Printf("Reordered size: %d bytes, bit-packed size: %d bytes", proposal.ReorderedSize, proposal.PackedSize)
+	if saving := proposal.Saving(); saving > 0 {
+		fmt.Printf(" (saves %d bytes per struct)\n", saving)
+	} else {
+		fmt.Println(" (no saving, the freed bytes are taken by alignment padding)")
+	}
+
+	return proposal
+}
+
+// AnalyzeNesting walks the structs nested and embedded in a struct, prints the
+// padding at every level and flags trailing zero-size fields and tail padding
+// that flattening the nested structs would reclaim. Like ProposeBitPacking it
+// keeps fields accessed with sync/atomic first when reordering would misalign them.
+func (a *StructAnalyzer) AnalyzeNesting(v any) layout.Nesting {
+	l := safeLayout(v)
+	nesting := l.Nesting()
+
+	fmt.Printf("\n=== %s Nested Layout Analysis ===\n", l.Name())
+	printPinned(l)
+	for _, level := range nesting.Levels {
+		kind := ""
+		switch {
+		case level.Embedded:
+			kind = fmt.Sprintf(" (embedded %s)", level.Type)
+		case level.Depth > 0:
+			kind = fmt.Sprintf(" (%s)", level.Type)
+		}
+		fmt.Printf("%s%s%s: %d bytes at offset %d, %d bytes padding (%d tail)\n",
+			strings.Repeat("  ", level.Depth), level.Path, kind, level.Size, level.Offset, level.Padding, level.TailPadding)
+	}
+
+	for depth, padding := range nesting.PaddingByDepth {
+		fmt.Printf("Padding at depth %d: %d bytes\n", depth, padding)
+	}
+
+	for _, level := range nesting.TrailingZeroSize() {
+		fmt.Printf("Warning: %s ends with zero-size field %s, which adds %d bytes of padding; move it to the front\n",
+			level.Path, level.TrailingZeroSize, level.TailPadding)
+	}
+
+	if nesting.NestedTailPadding > 0 {
+		fmt.Printf("Nested tail padding: %d bytes, flattened size: %d bytes (reordered: %d bytes)\n",
+			nesting.NestedTailPadding, nesting.FlattenedSize, nesting.OptimalSize)
+	}
+	if saving := nesting.Reclaimable(); saving > 0 {
+		fmt.Printf("Flattening the nested structs would save %d bytes per struct\n", saving)
+	} else {
+		fmt.Println("No padding to reclaim by flattening nested structs")
+	}
+
+	return nesting
+}
+
+// CheckAtomicAlignment finds the fields of a struct that its package's source
+// accesses with sync/atomic and checks they stay 8-byte aligned on every
+// 32-bit architecture, both as declared and in the optimal order, including
+// in every element of a slice of the struct. A reorder
+// that would misalign them is rejected in favour of an order that keeps the
+// atomic fields first. If the source cannot be scanned the result is marked
+// as not checked.
+func (a *StructAnalyzer) CheckAtomicAlignment(v any) AtomicAlignment {
+	l := layout.OfValue(v)
+	fmt.Printf("\n=== %s Atomic Alignment Check ===\n", l.Name())
+
+	alignment, err := atomicAlignment(l)
+	if err != nil {
+		fmt.Printf("Warning: atomic alignment not checked: %v\n", err)
+		return alignment
+	}
+	if len(alignment.Fields) == 0 {
+		fmt.Println("No fields accessed with sync/atomic")
+		return alignment
+	}
+	for _, usage := range atomicScans[l.Type.PkgPath()].report.ByStruct(l.Name()) {
+		fmt.Printf("Atomic access: %s\n", usage)
+	}
+
+	for _, check := range alignment.Checks {
+		arch := check.Arch
+		if err := check.Err(); err != nil {
+			fmt.Printf("Error: %v\n", err)
+		}
+		if !check.ReorderRejected() {
+			fmt.Printf("%s: atomic fields stay aligned in the optimal order\n", arch)
+			continue
+		}
+
+		names := make([]string, len(check.ReorderMisaligned))
+		for i, f := range check.ReorderMisaligned {
+			names[i] = fmt.Sprintf("%s at offset %d", f.Name, f.Offset)
+		}
+		fmt.Printf("%s: reorder rejected, it would misalign %s\n", arch, strings.Join(names, ", "))
+		fmt.Printf("%s: keep %s first instead (%d bytes, optimal order %d bytes)\n",
+			arch, strings.Join(check.Fields, ", "), check.SafeSize, check.OptimalSize)
+	}
+
+	return alignment
+}
+
+// atomicAlignment scans the package of a struct for atomic access, once per
+// package, and checks the atomic fields on every 32-bit architecture
+func atomicAlignment(l layout.Layout) (AtomicAlignment, error) {
+	pkgPath := l.Type.PkgPath()
+	scan, ok := atomicScans[pkgPath]
+	if !ok {
+		scan.report, scan.err = atomics.Scan(pkgPath)
+		atomicScans[pkgPath] = scan
+	}
+	if scan.err != nil {
+		return AtomicAlignment{}, fmt.Errorf("could not scan %s for atomic access: %w", pkgPath, scan.err)
+	}
+
+	alignment := AtomicAlignment{Fields: scan.report.Fields(l.Name())}
+	for _, arch := range layout.Archs32 {
+		if len(alignment.Fields) == 0 {
+			break
+		}
+		check, err := l.CheckAtomicAlignment(alignment.Fields, arch)
+		if err != nil {
+			return AtomicAlignment{}, err
+		}
+		alignment.Checks = append(alignment.Checks, check)
+	}
+	alignment.Checked = true
+	return alignment, nil
+}
+
+// safeLayout returns the layout of v. If the optimal order would misalign a
+// field accessed with sync/atomic, the layout keeps the atomic fields first
+// so that the sizes projected from it are for an order that is safe to apply.
+func safeLayout(v any) layout.Layout {
+	l := layout.OfValue(v)
+	if alignment, err := atomicAlignment(l); err == nil && alignment.Rejected() {
+		return l.PinAtomic(alignment.Fields)
+	}
+	return l
+}
+
+// printPinned notes the fields a layout keeps first for atomic access
+func printPinned(l layout.Layout) {
+	if len(l.Atomic) > 0 {
+		fmt.Printf("Keeping %s first, since reordering would misalign atomic access on 32-bit platforms\n",
+			strings.Join(l.Atomic, ", "))
+	}
+}
@@ -2,10 +2,10 @@ package structs
 
 import (
 	"fmt"
-	"math/rand"
 	model "mem-tests/model/struct"
+	"mem-tests/pkg/layout"
 	"mem-tests/pkg/memory"
-	"time"
+	"mem-tests/tests/fixtures"
 	"unsafe"
 )
 
@@ -17,45 +17,70 @@ func (t *MultiTypeStructTest) Name() string {
 	return "Multiple Struct Types Test"
 }
 
-// Run executes the test and returns results
-func (t *MultiTypeStructTest) Run() memory.TestResult {
-	result := memory.TestResult{
-		Name:       t.Name(),
-		OtherStats: make(map[string]any),
-	}
+// multiTypeCase measures one model with an object count typical for it
+type multiTypeCase struct {
+	name        string
+	objectCount int
+	optimFn     func(count int, observer *layout.RangeObserver) uint64
+	unoptimFn   func(count int) uint64
+	sample      any
+	unoptSample any
+}
 
-	// Define the test cases with custom object counts
-	testCases := []struct {
-		name        string
-		objectCount int
-		optimFn     func(count int) uint64
-		unoptimFn   func(count int) uint64
-	}{
+// multiTypeCases returns the models compared by the multi-type test
+func multiTypeCases() []multiTypeCase {
+	return []multiTypeCase{
 		{
 			name:        "API Request",
 			objectCount: 1000000, // High volume of API requests
 			optimFn:     testAPIOptimized,
 			unoptimFn:   testAPIUnoptimized,
+			sample:      model.APIOptimizedStruct{},
+			unoptSample: model.APIUnoptimizedStruct{},
 		},
 		{
 			name:        "Config",
 			objectCount: 10000, // Fewer config objects
 			optimFn:     testConfigOptimized,
 			unoptimFn:   testConfigUnoptimized,
+			sample:      model.ConfigOptimizedStruct{},
+			unoptSample: model.ConfigUnoptimizedStruct{},
 		},
 		{
 			name:        "GraphQL",
 			objectCount: 500000, // Medium volume of GraphQL objects
 			optimFn:     testGraphQLOptimized,
 			unoptimFn:   testGraphQLUnoptimized,
+			sample:      model.GraphQLOptimizedStruct{},
+			unoptSample: model.GraphQLUnoptimizedStruct{},
 		},
 		{
 			name:        "Database Entity",
 			objectCount: 250000, // Medium volume of DB entities
 			optimFn:     testDBEntityOptimized,
 			unoptimFn:   testDBEntityUnoptimized,
+			sample:      model.DBEntityOptimizedStruct{},
+			unoptSample: model.DBEntityUnoptimizedStruct{},
+		},
+		{
+			name:        "Cache Stats",
+			objectCount: 100000, // One per cache shard, updated with sync/atomic
+			optimFn:     testCacheStatsOptimized,
+			unoptimFn:   testCacheStatsUnoptimized,
+			sample:      model.CacheStatsOptimizedStruct{},
+			unoptSample: model.CacheStatsUnoptimizedStruct{},
 		},
 	}
+}
+
+// Run executes the test and returns results
+func (t *MultiTypeStructTest) Run() memory.TestResult {
+	result := memory.TestResult{
+		Name:       t.Name(),
+		OtherStats: make(map[string]any),
+	}
+
+	testCases := multiTypeCases()
 
 	// Store detailed results for each test case
 	typeResults := make(map[string]map[string]interface{})
@@ -67,7 +92,7 @@ func (t *MultiTypeStructTest) Run() memory.TestResult {
 
 		// Run optimized version
 		fmt.Printf("\n--- Optimized %s Struct ---\n", tc.name)
-		optimizedMem := tc.optimFn(tc.objectCount)
+		optimizedMem := tc.optimFn(tc.objectCount, nil)
 
 		// Force GC to clean up
 		memory.CleanupAfterTest()
@@ -77,30 +102,45 @@ func (t *MultiTypeStructTest) Run() memory.TestResult {
 		unoptimizedMem := tc.unoptimFn(tc.objectCount)
 
 		// Calculate savings
-		memorySaved := unoptimizedMem - optimizedMem
+		typeResult := memory.NewTypeResult(tc.objectCount, optimizedMem, unoptimizedMem)
+		memorySaved := typeResult["MemorySaved"].(uint64)
 		totalSaving += memorySaved
 
-		var savingPct float64
-		if unoptimizedMem > 0 {
-			savingPct = float64(memorySaved) / float64(unoptimizedMem) * 100
-		}
-
 		// Print results
 		fmt.Printf("\n--- %s Results ---\n", tc.name)
 		fmt.Printf("Optimized memory: %d bytes\n", optimizedMem)
 		fmt.Printf("Unoptimized memory: %d bytes\n", unoptimizedMem)
-		fmt.Printf("Memory saved: %d bytes (%.2f%%)\n", memorySaved, savingPct)
-		fmt.Printf("Memory saved per object: %.2f bytes\n", float64(memorySaved)/float64(tc.objectCount))
+		if extra := memory.Growth(unoptimizedMem, optimizedMem); extra > 0 {
+			// The saving is clamped at zero, so report the regression itself
+			fmt.Printf("Memory regressed: %d extra bytes (%.2f%%)\n", extra, typeResult["SavingPercent"])
+			fmt.Printf("Extra memory per object: %.2f bytes\n", float64(extra)/float64(tc.objectCount))
+		} else {
+			fmt.Printf("Memory saved: %d bytes (%.2f%%)\n", memorySaved, typeResult["SavingPercent"])
+			fmt.Printf("Memory saved per object: %.2f bytes\n", typeResult["PerObjectSaving"])
+		}
+
+		// Compare the layouts, rejecting an order that breaks atomic access
+		(&StructAnalyzer{}).AnalyzeStructLayout(tc.name, tc.sample, tc.unoptSample, tc.objectCount)
+
+		// Propose packing the bool fields into a bitset
+		bitPacking := (&StructAnalyzer{}).ProposeBitPacking(tc.sample)
+		typeResult["PackableFlags"] = len(bitPacking.Flags)
+		typeResult["BitPackedSize"] = bitPacking.PackedSize
+
+		// Look for padding hidden inside nested structs
+		nesting := (&StructAnalyzer{}).AnalyzeNesting(tc.sample)
+		typeResult["NestedTailPadding"] = nesting.NestedTailPadding
+		typeResult["FlattenedSize"] = nesting.FlattenedSize
+
+		// Make sure the reorder keeps atomically accessed fields aligned on 32-bit platforms
+		(&StructAnalyzer{}).CheckAtomicAlignment(tc.sample).Record(typeResult)
+
+		// Keep the layouts so the visualizer can draw them
+		typeResult["OptimizedLayout"] = layout.OfValue(tc.sample)
+		typeResult["UnoptimizedLayout"] = layout.OfValue(tc.unoptSample)
 
 		// Store detailed results
-		typeResults[tc.name] = map[string]interface{}{
-			"ObjectCount":       tc.objectCount,
-			"OptimizedMemory":   optimizedMem,
-			"UnoptimizedMemory": unoptimizedMem,
-			"MemorySaved":       memorySaved,
-			"SavingPercent":     savingPct,
-			"PerObjectSaving":   float64(memorySaved) / float64(tc.objectCount),
-		}
+		typeResults[tc.name] = typeResult
 
 		// Force GC to clean up
 		memory.CleanupAfterTest()
@@ -115,37 +155,29 @@ func (t *MultiTypeStructTest) Run() memory.TestResult {
 }
 
 // API struct test functions
-func testAPIOptimized(count int) uint64 {
+func testAPIOptimized(count int, observer *layout.RangeObserver) uint64 {
 	startMem := memory.Usage()
 
 	// Create a slice to hold all the structs
 	structs := make([]model.APIOptimizedStruct, count)
 
 	// Initialize with some data
-	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	gen := fixtures.New(fixtures.API.Optimized)
 	for i := 0; i < count; i++ {
-		structs[i] = model.APIOptimizedStruct{
-			RequestID:     uint64(rnd.Int63()),
-			UserID:        uint64(rnd.Intn(1000000)),
-			Timestamp:     time.Now().UnixNano(),
-			SessionID:     uint64(rnd.Int63()),
-			StatusCode:    200,
-			Latency:       float32(rnd.Float64() * 100),
-			APIVersion:    1,
-			Method:        byte('G'), // GET
-			Authenticated: true,
-			Cached:        false,
-		}
+		structs[i] = gen(i)
 	}
 
 	endMem := memory.Usage()
-	memUsed := endMem - startMem
+	memUsed := memory.Growth(startMem, endMem)
 
 	// Prevent optimizer from removing our structs
 	fmt.Printf("Sample API struct size: %d bytes\n", unsafe.Sizeof(structs[0]))
 	fmt.Printf("Memory used: %d bytes (%.2f MB)\n", memUsed, float64(memUsed)/(1024*1024))
 	fmt.Printf("Memory per struct: %.2f bytes\n", float64(memUsed)/float64(count))
 
+	// Record the values the population holds when it is observed
+	observePopulation(observer, structs)
+
 	return memUsed
 }
 
@@ -156,24 +188,13 @@ func testAPIUnoptimized(count int) uint64 {
 	structs := make([]model.APIUnoptimizedStruct, count)
 
 	// Initialize with same data
-	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	gen := fixtures.New(fixtures.API.Unoptimized)
 	for i := 0; i < count; i++ {
-		structs[i] = model.APIUnoptimizedStruct{
-			Method:        byte('G'), // GET
-			Authenticated: true,
-			UserID:        uint64(rnd.Intn(1000000)),
-			Cached:        false,
-			StatusCode:    200,
-			APIVersion:    1,
-			RequestID:     uint64(rnd.Int63()),
-			Timestamp:     time.Now().UnixNano(),
-			SessionID:     uint64(rnd.Int63()),
-			Latency:       float32(rnd.Float64() * 100),
-		}
+		structs[i] = gen(i)
 	}
 
 	endMem := memory.Usage()
-	memUsed := endMem - startMem
+	memUsed := memory.Growth(startMem, endMem)
 
 	// Prevent optimizer from removing our structs
 	fmt.Printf("Sample API struct size: %d bytes\n", unsafe.Sizeof(structs[0]))
@@ -184,37 +205,29 @@ func testAPIUnoptimized(count int) uint64 {
 }
 
 // Config struct test functions
-func testConfigOptimized(count int) uint64 {
+func testConfigOptimized(count int, observer *layout.RangeObserver) uint64 {
 	startMem := memory.Usage()
 
 	// Create a slice to hold all the structs
 	structs := make([]model.ConfigOptimizedStruct, count)
 
 	// Initialize with some data
-	envs := []string{"dev", "staging", "production"}
+	gen := fixtures.New(fixtures.Config.Optimized)
 	for i := 0; i < count; i++ {
-		structs[i] = model.ConfigOptimizedStruct{
-			Name:           "app-config",
-			Description:    "Main application configuration",
-			Environment:    envs[i%len(envs)],
-			UpdatedAt:      time.Now().Unix(),
-			CreatedAt:      time.Now().Add(-24 * time.Hour).Unix(),
-			MaxConnections: 100,
-			Timeout:        30,
-			Port:           8080,
-			Debug:          false,
-			Enabled:        true,
-		}
+		structs[i] = gen(i)
 	}
 
 	endMem := memory.Usage()
-	memUsed := endMem - startMem
+	memUsed := memory.Growth(startMem, endMem)
 
 	// Prevent optimizer from removing our structs
 	fmt.Printf("Sample Config struct size: %d bytes\n", unsafe.Sizeof(structs[0]))
 	fmt.Printf("Memory used: %d bytes (%.2f MB)\n", memUsed, float64(memUsed)/(1024*1024))
 	fmt.Printf("Memory per struct: %.2f bytes\n", float64(memUsed)/float64(count))
 
+	// Record the values the population holds when it is observed
+	observePopulation(observer, structs)
+
 	return memUsed
 }
 
@@ -225,24 +238,13 @@ func testConfigUnoptimized(count int) uint64 {
 	structs := make([]model.ConfigUnoptimizedStruct, count)
 
 	// Initialize with same data
-	envs := []string{"dev", "staging", "production"}
+	gen := fixtures.New(fixtures.Config.Unoptimized)
 	for i := 0; i < count; i++ {
-		structs[i] = model.ConfigUnoptimizedStruct{
-			Debug:          false,
-			Enabled:        true,
-			Port:           8080,
-			Name:           "app-config",
-			Timeout:        30,
-			Environment:    envs[i%len(envs)],
-			MaxConnections: 100,
-			CreatedAt:      time.Now().Add(-24 * time.Hour).Unix(),
-			Description:    "Main application configuration",
-			UpdatedAt:      time.Now().Unix(),
-		}
+		structs[i] = gen(i)
 	}
 
 	endMem := memory.Usage()
-	memUsed := endMem - startMem
+	memUsed := memory.Growth(startMem, endMem)
 
 	// Prevent optimizer from removing our structs
 	fmt.Printf("Sample Config struct size: %d bytes\n", unsafe.Sizeof(structs[0]))
@@ -253,40 +255,30 @@ func testConfigUnoptimized(count int) uint64 {
 }
 
 // GraphQL struct test functions
-func testGraphQLOptimized(count int) uint64 {
+func testGraphQLOptimized(count int, observer *layout.RangeObserver) uint64 {
 	startMem := memory.Usage()
 
 	// Create a slice to hold all the structs
 	structs := make([]model.GraphQLOptimizedStruct, count)
 
 	// Initialize with some data
-	operations := []string{"query", "mutation", "subscription"}
-	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	gen := fixtures.New(fixtures.GraphQL.Optimized)
 
 	for i := 0; i < count; i++ {
-		structs[i] = model.GraphQLOptimizedStruct{
-			QueryID:         fmt.Sprintf("query-%d", i),
-			Operation:       operations[i%len(operations)],
-			ClientID:        fmt.Sprintf("client-%d", i%1000),
-			Timestamp:       time.Now().UnixNano(),
-			Duration:        int64(rnd.Intn(1000)),
-			Depth:           int32(rnd.Intn(10) + 1),
-			ComplexityScore: float32(rnd.Float64() * 100),
-			FragmentCount:   uint16(rnd.Intn(5)),
-			IsMutation:      i%3 == 1,
-			HasVariables:    i%2 == 0,
-			Cached:          i%5 == 0,
-		}
+		structs[i] = gen(i)
 	}
 
 	endMem := memory.Usage()
-	memUsed := endMem - startMem
+	memUsed := memory.Growth(startMem, endMem)
 
 	// Prevent optimizer from removing our structs
 	fmt.Printf("Sample GraphQL struct size: %d bytes\n", unsafe.Sizeof(structs[0]))
 	fmt.Printf("Memory used: %d bytes (%.2f MB)\n", memUsed, float64(memUsed)/(1024*1024))
 	fmt.Printf("Memory per struct: %.2f bytes\n", float64(memUsed)/float64(count))
 
+	// Record the values the population holds when it is observed
+	observePopulation(observer, structs)
+
 	return memUsed
 }
 
@@ -297,27 +289,14 @@ func testGraphQLUnoptimized(count int) uint64 {
 	structs := make([]model.GraphQLUnoptimizedStruct, count)
 
 	// Initialize with same data
-	operations := []string{"query", "mutation", "subscription"}
-	rnd := rand.New(rand.NewSource(time.Now().UnixNano()))
+	gen := fixtures.New(fixtures.GraphQL.Unoptimized)
 
 	for i := 0; i < count; i++ {
-		structs[i] = model.GraphQLUnoptimizedStruct{
-			IsMutation:      i%3 == 1,
-			Cached:          i%5 == 0,
-			Depth:           int32(rnd.Intn(10) + 1),
-			Operation:       operations[i%len(operations)],
-			HasVariables:    i%2 == 0,
-			FragmentCount:   uint16(rnd.Intn(5)),
-			Timestamp:       time.Now().UnixNano(),
-			QueryID:         fmt.Sprintf("query-%d", i),
-			ComplexityScore: float32(rnd.Float64() * 100),
-			ClientID:        fmt.Sprintf("client-%d", i%1000),
-			Duration:        int64(rnd.Intn(1000)),
-		}
+		structs[i] = gen(i)
 	}
 
 	endMem := memory.Usage()
-	memUsed := endMem - startMem
+	memUsed := memory.Growth(startMem, endMem)
 
 	// Prevent optimizer from removing our structs
 	fmt.Printf("Sample GraphQL struct size: %d bytes\n", unsafe.Sizeof(structs[0]))
@@ -328,41 +307,30 @@ func testGraphQLUnoptimized(count int) uint64 {
 }
 
 // Database Entity struct test functions
-func testDBEntityOptimized(count int) uint64 {
+func testDBEntityOptimized(count int, observer *layout.RangeObserver) uint64 {
 	startMem := memory.Usage()
 
 	// Create a slice to hold all the structs
 	structs := make([]model.DBEntityOptimizedStruct, count)
 
 	// Initialize with some data
-	now := time.Now()
-	rnd := rand.New(rand.NewSource(now.UnixNano()))
+	gen := fixtures.New(fixtures.DBEntity.Optimized)
 
 	for i := 0; i < count; i++ {
-		structs[i] = model.DBEntityOptimizedStruct{
-			ID:          fmt.Sprintf("user-%d", i),
-			Name:        fmt.Sprintf("User %d", i),
-			Email:       fmt.Sprintf("user%d@example.com", i),
-			CreatedAt:   now.Add(-time.Duration(rnd.Intn(10000)) * time.Hour),
-			UpdatedAt:   now,
-			LastLoginAt: now.Add(-time.Duration(rnd.Intn(100)) * time.Hour),
-			LoginCount:  int32(rnd.Intn(100)),
-			Status:      int32(rnd.Intn(3)),
-			AccessLevel: uint16(rnd.Intn(5)),
-			IsActive:    true,
-			IsAdmin:     i%50 == 0, // 2% are admins
-			HasMFA:      i%3 == 0,  // 33% have MFA
-		}
+		structs[i] = gen(i)
 	}
 
 	endMem := memory.Usage()
-	memUsed := endMem - startMem
+	memUsed := memory.Growth(startMem, endMem)
 
 	// Prevent optimizer from removing our structs
 	fmt.Printf("Sample DB Entity struct size: %d bytes\n", unsafe.Sizeof(structs[0]))
 	fmt.Printf("Memory used: %d bytes (%.2f MB)\n", memUsed, float64(memUsed)/(1024*1024))
 	fmt.Printf("Memory per struct: %.2f bytes\n", float64(memUsed)/float64(count))
 
+	// Record the values the population holds when it is observed
+	observePopulation(observer, structs)
+
 	return memUsed
 }
 
@@ -373,28 +341,14 @@ func testDBEntityUnoptimized(count int) uint64 {
 	structs := make([]model.DBEntityUnoptimizedStruct, count)
 
 	// Initialize with same data
-	now := time.Now()
-	rnd := rand.New(rand.NewSource(now.UnixNano()))
+	gen := fixtures.New(fixtures.DBEntity.Unoptimized)
 
 	for i := 0; i < count; i++ {
-		structs[i] = model.DBEntityUnoptimizedStruct{
-			IsActive:    true,
-			IsAdmin:     i%50 == 0, // 2% are admins
-			AccessLevel: uint16(rnd.Intn(5)),
-			Email:       fmt.Sprintf("user%d@example.com", i),
-			Status:      int32(rnd.Intn(3)),
-			LastLoginAt: now.Add(-time.Duration(rnd.Intn(100)) * time.Hour),
-			HasMFA:      i%3 == 0, // 33% have MFA
-			ID:          fmt.Sprintf("user-%d", i),
-			Name:        fmt.Sprintf("User %d", i),
-			LoginCount:  int32(rnd.Intn(100)),
-			CreatedAt:   now.Add(-time.Duration(rnd.Intn(10000)) * time.Hour),
-			UpdatedAt:   now,
-		}
+		structs[i] = gen(i)
 	}
 
 	endMem := memory.Usage()
-	memUsed := endMem - startMem
+	memUsed := memory.Growth(startMem, endMem)
 
 	// Prevent optimizer from removing our structs
 	fmt.Printf("Sample DB Entity struct size: %d bytes\n", unsafe.Sizeof(structs[0]))
@@ -403,3 +357,55 @@ func testDBEntityUnoptimized(count int) uint64 {
 
 	return memUsed
 }
+
+// Cache stats struct test functions
+func testCacheStatsOptimized(count int, observer *layout.RangeObserver) uint64 {
+	startMem := memory.Usage()
+
+	// Create a slice to hold all the structs
+	structs := make([]model.CacheStatsOptimizedStruct, count)
+
+	// Initialize with some data
+	gen := fixtures.New((*fixtures.Values).CacheStats)
+
+	for i := 0; i < count; i++ {
+		gen(i).FillOptimized(&structs[i])
+	}
+
+	endMem := memory.Usage()
+	memUsed := memory.Growth(startMem, endMem)
+
+	// Prevent optimizer from removing our structs
+	fmt.Printf("Sample Cache Stats struct size: %d bytes\n", unsafe.Sizeof(structs[0]))
+	fmt.Printf("Memory used: %d bytes (%.2f MB)\n", memUsed, float64(memUsed)/(1024*1024))
+	fmt.Printf("Memory per struct: %.2f bytes\n", float64(memUsed)/float64(count))
+
+	// Record the values the population holds when it is observed
+	observePopulation(observer, structs)
+
+	return memUsed
+}
+
+func testCacheStatsUnoptimized(count int) uint64 {
+	startMem := memory.Usage()
+
+	// Create a slice to hold all the structs
+	structs := make([]model.CacheStatsUnoptimizedStruct, count)
+
+	// Initialize with same data
+	gen := fixtures.New((*fixtures.Values).CacheStats)
+
+	for i := 0; i < count; i++ {
+		gen(i).FillUnoptimized(&structs[i])
+	}
+
+	endMem := memory.Usage()
+	memUsed := memory.Growth(startMem, endMem)
+
+	// Prevent optimizer from removing our structs
+	fmt.Printf("Sample Cache Stats struct size: %d bytes\n", unsafe.Sizeof(structs[0]))
+	fmt.Printf("Memory used: %d bytes (%.2f MB)\n", memUsed, float64(memUsed)/(1024*1024))
+	fmt.Printf("Memory per struct: %.2f bytes\n", float64(memUsed)/float64(count))
+
+	return memUsed
+}
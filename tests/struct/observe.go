@@ -0,0 +1,81 @@
+package structs
+
+import (
+	model "mem-tests/model/struct"
+	"mem-tests/pkg/layout"
+)
+
+// Population is the optimized population of one model as the field order
+// tests build it
+type Population struct {
+	// Name of the model
+	Name string
+
+	// Count is the number of objects in the population
+	Count int
+
+	// Observe builds the population with the same code, object count and seed
+	// as the field order tests and returns the ranges its integer fields took
+	Observe func() *layout.RangeObserver
+}
+
+// Populations returns the population of every model the field order tests
+// measure, populated with the profiles set on the small and large struct tests
+func Populations(small *StructOrderTest, big *StructBigTest) []Population {
+	populations := []Population{
+		{
+			Name:  "Small Struct",
+			Count: numObjects,
+			Observe: func() *layout.RangeObserver {
+				observer := newRangeObserver(model.OptimizedStruct{})
+				testSmallOptimizedStructs(small.profile, observer)
+				return observer
+			},
+		},
+		{
+			Name:  "Large Struct",
+			Count: numObjects,
+			Observe: func() *layout.RangeObserver {
+				observer := newRangeObserver(model.LargeOptimizedStruct{})
+				testLargeOptimizedStructs(big.profile, observer)
+				return observer
+			},
+		},
+	}
+
+	for _, tc := range multiTypeCases() {
+		populations = append(populations, Population{
+			Name:  tc.name,
+			Count: tc.objectCount,
+			Observe: func() *layout.RangeObserver {
+				observer := newRangeObserver(tc.sample)
+				tc.optimFn(tc.objectCount, observer)
+				return observer
+			},
+		})
+	}
+	return populations
+}
+
+// observePopulation records the integer fields of every object of a
+// population when an observer is given. It runs once the memory used by the
+// population is measured, so observing does not skew the measurement.
+func observePopulation[T any](observer *layout.RangeObserver, structs []T) {
+	if observer == nil {
+		return
+	}
+	for i := range structs {
+		observer.Observe(&structs[i])
+	}
+}
+
+// newRangeObserver creates an observer for the struct type of v. Fields its
+// package accesses with sync/atomic keep their width, and the narrowed size
+// is projected from the order that keeps them aligned on 32-bit platforms.
+func newRangeObserver(v any) *layout.RangeObserver {
+	var atomic []string
+	if alignment, err := atomicAlignment(layout.OfValue(v)); err == nil {
+		atomic = alignment.Fields
+	}
+	return layout.NewRangeObserver(safeLayout(v), atomic)
+}
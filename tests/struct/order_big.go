@@ -2,15 +2,24 @@ package structs
 
 import (
 	"fmt"
-	"math/rand"
 	model "mem-tests/model/struct"
+	"mem-tests/pkg/layout"
 	"mem-tests/pkg/memory"
-	"time"
+	"mem-tests/pkg/populate"
+	"mem-tests/tests/fixtures"
 	"unsafe"
 )
 
 // StructBigTest tests memory efficiency with different struct field orders for large structs
-type StructBigTest struct{}
+type StructBigTest struct {
+	// profile controls how reference fields are populated
+	profile populate.Profile
+}
+
+// SetProfile selects the population profile for reference fields
+func (t *StructBigTest) SetProfile(p populate.Profile) {
+	t.profile = p
+}
 
 // Name returns the name of this test
 func (t *StructBigTest) Name() string {
@@ -23,36 +32,45 @@ func (t *StructBigTest) Run() memory.TestResult {
 		Name:       t.Name(),
 		OtherStats: make(map[string]any),
 	}
+	result.OtherStats["Profile"] = t.profile.String()
 
 	// Analyze struct layouts
 	analyzeLargeStructLayout(&result)
 
+	// Look for bool fields that could be packed into a bitset
+	bitPacking := (&StructAnalyzer{}).ProposeBitPacking(model.LargeOptimizedStruct{})
+	result.OtherStats["BitPackedStructSize"] = bitPacking.PackedSize
+
+	// Look for padding hidden inside nested structs
+	nesting := (&StructAnalyzer{}).AnalyzeNesting(model.LargeOptimizedStruct{})
+	result.OtherStats["NestedTailPadding"] = nesting.NestedTailPadding
+	result.OtherStats["FlattenedStructSize"] = nesting.FlattenedSize
+
+	// Make sure the reorder keeps atomically accessed fields aligned on 32-bit platforms
+	(&StructAnalyzer{}).CheckAtomicAlignment(model.LargeOptimizedStruct{}).Record(result.OtherStats)
+
+	// Keep the layouts so the visualizer can draw them
+	result.OtherStats["OptimizedLayout"] = layout.OfValue(model.LargeOptimizedStruct{})
+	result.OtherStats["UnoptimizedLayout"] = layout.OfValue(model.LargeUnoptimizedStruct{})
+
 	// Test with optimized structs
 	fmt.Println("\n=== Testing LargeOptimizedStruct (largest to smallest) ===")
-	optimizedMem := testLargeOptimizedStructs()
+	optimizedMem, optimizedRetained := testLargeOptimizedStructs(t.profile, nil)
 
 	// Force GC to clean up
 	memory.CleanupAfterTest()
 
 	// Test with unoptimized structs
 	fmt.Println("\n=== Testing LargeUnoptimizedStruct (mixed order) ===")
-	unoptimizedMem := testLargeUnoptimizedStructs()
+	unoptimizedMem, unoptimizedRetained := testLargeUnoptimizedStructs(t.profile)
 
 	// Store results
-	result.MemoryUsed = unoptimizedMem - optimizedMem
-	result.OtherStats["OptimizedMemory"] = optimizedMem
-	result.OtherStats["UnoptimizedMemory"] = unoptimizedMem
-	result.PerObjectSize = float64(result.MemoryUsed) / float64(numObjects)
+	analyzer := &StructAnalyzer{}
+	analyzer.CalculateMemorySavings(&result, optimizedMem, unoptimizedMem,
+		unsafe.Sizeof(model.LargeOptimizedStruct{}), unsafe.Sizeof(model.LargeUnoptimizedStruct{}))
 
-	if unoptimizedMem > optimizedMem {
-		result.OtherStats["MemorySavingPercent"] = float64(unoptimizedMem-optimizedMem) / float64(unoptimizedMem) * 100
-	} else {
-		result.OtherStats["MemorySavingPercent"] = 0.0
-	}
-
-	// Print struct sizes
-	result.OtherStats["OptimizedStructSize"] = unsafe.Sizeof(model.LargeOptimizedStruct{})
-	result.OtherStats["UnoptimizedStructSize"] = unsafe.Sizeof(model.LargeUnoptimizedStruct{})
+	// Record per-object retained sizes
+	analyzer.RecordRetainedSizes(&result, optimizedRetained, unoptimizedRetained)
 
 	return result
 }
@@ -82,44 +100,21 @@ func analyzeLargeStructLayout(result *memory.TestResult) {
 	}
 }
 
-func testLargeOptimizedStructs() uint64 {
+func testLargeOptimizedStructs(profile populate.Profile, observer *layout.RangeObserver) (uint64, memory.RetainedSize) {
 	startMem := memory.Usage()
 
 	// Create a slice to hold all the structs
 	structs := make([]model.LargeOptimizedStruct, numObjects)
 
 	// Initialize each struct with realistic data
-	now := time.Now()
-	rnd := rand.New(rand.NewSource(now.UnixNano()))
+	gen := fixtures.New(fixtures.Large(profile).Optimized)
 
 	for i := 0; i < numObjects; i++ {
-		structs[i] = model.LargeOptimizedStruct{
-			CreatedAt:         now.Add(-time.Duration(rnd.Intn(3600)) * time.Second),
-			UpdatedAt:         now,
-			TransactionID:     uint64(rnd.Int63()),
-			UserID:            uint64(rnd.Intn(1000000)),
-			OrderID:           uint64(rnd.Int63()),
-			RequestTimestamp:  now.Add(-time.Duration(rnd.Intn(500)) * time.Millisecond).UnixNano(),
-			ResponseTimestamp: now.UnixNano(),
-			StatusCode:        200,
-			ResponseCode:      0,
-			RequestCount:      int32(1 + rnd.Intn(5)),
-			RetryAttempts:     int32(rnd.Intn(3)),
-			ServiceTime:       float32(rnd.Float64() * 100),
-			CPUTime:           float32(rnd.Float64() * 50),
-			ErrorCode:         0,
-			ProtocolVersion:   2,
-			ServerRegion:      uint16(rnd.Intn(10)),
-			IsSuccess:         true,
-			IsRetry:           false,
-			IsCached:          rnd.Float32() < 0.3, // 30% cache hit rate
-			Priority:          uint8(rnd.Intn(5)),
-			CompressionLevel:  uint8(rnd.Intn(10)),
-		}
+		structs[i] = gen(i)
 	}
 
 	endMem := memory.Usage()
-	memUsed := endMem - startMem
+	memUsed := memory.Growth(startMem, endMem)
 	fmt.Printf("Memory used: %d bytes (%s)\n", memUsed, memory.FormatBytes(memUsed))
 	fmt.Printf("Memory per struct: %.2f bytes (%s)\n",
 		float64(memUsed)/float64(numObjects),
@@ -128,47 +123,31 @@ func testLargeOptimizedStructs() uint64 {
 	// Prevent optimizer from removing our structs before measurements
 	fmt.Printf("Sample value: %v\n", structs[0].TransactionID)
 
-	return memUsed
+	// Measure what a single object retains, including referenced data
+	retained := memory.DeepSize(structs[0])
+	fmt.Printf("Sample shallow size: %d bytes, retained size: %d bytes\n", retained.Shallow, retained.Retained)
+
+	// Record the values the population holds when it is observed
+	observePopulation(observer, structs)
+
+	return memUsed, retained
 }
 
-func testLargeUnoptimizedStructs() uint64 {
+func testLargeUnoptimizedStructs(profile populate.Profile) (uint64, memory.RetainedSize) {
 	startMem := memory.Usage()
 
 	// Create a slice to hold all the structs
 	structs := make([]model.LargeUnoptimizedStruct, numObjects)
 
 	// Initialize each struct with the same realistic data as optimized version
-	now := time.Now()
-	rnd := rand.New(rand.NewSource(now.UnixNano()))
+	gen := fixtures.New(fixtures.Large(profile).Unoptimized)
 
 	for i := 0; i < numObjects; i++ {
-		structs[i] = model.LargeUnoptimizedStruct{
-			IsSuccess:         true,
-			Priority:          uint8(rnd.Intn(5)),
-			UserID:            uint64(rnd.Intn(1000000)),
-			IsRetry:           false,
-			StatusCode:        200,
-			ServiceTime:       float32(rnd.Float64() * 100),
-			ErrorCode:         0,
-			CreatedAt:         now.Add(-time.Duration(rnd.Intn(3600)) * time.Second),
-			TransactionID:     uint64(rnd.Int63()),
-			IsCached:          rnd.Float32() < 0.3, // 30% cache hit rate
-			ResponseCode:      0,
-			ProtocolVersion:   2,
-			ServerRegion:      uint16(rnd.Intn(10)),
-			UpdatedAt:         now,
-			OrderID:           uint64(rnd.Int63()),
-			RequestTimestamp:  now.Add(-time.Duration(rnd.Intn(500)) * time.Millisecond).UnixNano(),
-			ResponseTimestamp: now.UnixNano(),
-			RequestCount:      int32(1 + rnd.Intn(5)),
-			RetryAttempts:     int32(rnd.Intn(3)),
-			CPUTime:           float32(rnd.Float64() * 50),
-			CompressionLevel:  uint8(rnd.Intn(10)),
-		}
+		structs[i] = gen(i)
 	}
 
 	endMem := memory.Usage()
-	memUsed := endMem - startMem
+	memUsed := memory.Growth(startMem, endMem)
 	fmt.Printf("Memory used: %d bytes (%s)\n", memUsed, memory.FormatBytes(memUsed))
 	fmt.Printf("Memory per struct: %.2f bytes (%s)\n",
 		float64(memUsed)/float64(numObjects),
@@ -177,5 +156,9 @@ func testLargeUnoptimizedStructs() uint64 {
 	// Prevent optimizer from removing our structs before measurements
 	fmt.Printf("Sample value: %v\n", structs[0].TransactionID)
 
-	return memUsed
+	// Measure what a single object retains, including referenced data
+	retained := memory.DeepSize(structs[0])
+	fmt.Printf("Sample shallow size: %d bytes, retained size: %d bytes\n", retained.Shallow, retained.Retained)
+
+	return memUsed, retained
 }
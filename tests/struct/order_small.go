@@ -3,12 +3,23 @@ package structs
 import (
 	"fmt"
 	model "mem-tests/model/struct"
+	"mem-tests/pkg/layout"
 	"mem-tests/pkg/memory"
+	"mem-tests/pkg/populate"
+	"mem-tests/tests/fixtures"
 	"unsafe"
 )
 
 // StructOrderTest tests memory efficiency with different struct field orders
-type StructOrderTest struct{}
+type StructOrderTest struct {
+	// profile controls how reference fields are populated
+	profile populate.Profile
+}
+
+// SetProfile selects the population profile for reference fields
+func (t *StructOrderTest) SetProfile(p populate.Profile) {
+	t.profile = p
+}
 
 // Name returns the name of this test
 func (t *StructOrderTest) Name() string {
@@ -21,36 +32,45 @@ func (t *StructOrderTest) Run() memory.TestResult {
 		Name:       t.Name(),
 		OtherStats: make(map[string]any),
 	}
+	result.OtherStats["Profile"] = t.profile.String()
 
 	// Analyze struct layouts
 	analyzeSmallStructLayout(&result)
 
+	// Look for bool fields that could be packed into a bitset
+	bitPacking := (&StructAnalyzer{}).ProposeBitPacking(model.OptimizedStruct{})
+	result.OtherStats["BitPackedStructSize"] = bitPacking.PackedSize
+
+	// Look for padding hidden inside nested structs
+	nesting := (&StructAnalyzer{}).AnalyzeNesting(model.OptimizedStruct{})
+	result.OtherStats["NestedTailPadding"] = nesting.NestedTailPadding
+	result.OtherStats["FlattenedStructSize"] = nesting.FlattenedSize
+
+	// Make sure the reorder keeps atomically accessed fields aligned on 32-bit platforms
+	(&StructAnalyzer{}).CheckAtomicAlignment(model.OptimizedStruct{}).Record(result.OtherStats)
+
+	// Keep the layouts so the visualizer can draw them
+	result.OtherStats["OptimizedLayout"] = layout.OfValue(model.OptimizedStruct{})
+	result.OtherStats["UnoptimizedLayout"] = layout.OfValue(model.UnoptimizedStruct{})
+
 	// Test with optimized structs
 	fmt.Println("\n=== Testing OptimizedStruct (largest to smallest) ===")
-	optimizedMem := testSmallOptimizedStructs()
+	optimizedMem, optimizedRetained := testSmallOptimizedStructs(t.profile, nil)
 
 	// Force GC to clean up
 	memory.CleanupAfterTest()
 
 	// Test with unoptimized structs
 	fmt.Println("\n=== Testing UnoptimizedStruct (smallest to largest) ===")
-	unoptimizedMem := testSmallUnoptimizedStructs()
+	unoptimizedMem, unoptimizedRetained := testSmallUnoptimizedStructs(t.profile)
 
 	// Store results
-	result.MemoryUsed = unoptimizedMem - optimizedMem
-	result.OtherStats["OptimizedMemory"] = optimizedMem
-	result.OtherStats["UnoptimizedMemory"] = unoptimizedMem
-	result.PerObjectSize = float64(result.MemoryUsed) / float64(numObjects)
-
-	if unoptimizedMem > optimizedMem {
-		result.OtherStats["MemorySavingPercent"] = float64(unoptimizedMem-optimizedMem) / float64(unoptimizedMem) * 100
-	} else {
-		result.OtherStats["MemorySavingPercent"] = 0.0
-	}
+	analyzer := &StructAnalyzer{}
+	analyzer.CalculateMemorySavings(&result, optimizedMem, unoptimizedMem,
+		unsafe.Sizeof(model.OptimizedStruct{}), unsafe.Sizeof(model.UnoptimizedStruct{}))
 
-	// Print struct sizes
-	result.OtherStats["OptimizedStructSize"] = unsafe.Sizeof(model.OptimizedStruct{})
-	result.OtherStats["UnoptimizedStructSize"] = unsafe.Sizeof(model.UnoptimizedStruct{})
+	// Record per-object retained sizes
+	analyzer.RecordRetainedSizes(&result, optimizedRetained, unoptimizedRetained)
 
 	return result
 }
@@ -99,65 +119,59 @@ func analyzeSmallStructLayout(result *memory.TestResult) {
 	}
 }
 
-func testSmallOptimizedStructs() uint64 {
+func testSmallOptimizedStructs(profile populate.Profile, observer *layout.RangeObserver) (uint64, memory.RetainedSize) {
 	startMem := memory.Usage()
 
 	// Create a slice to hold all the structs
 	structs := make([]model.OptimizedStruct, numObjects)
 
-	// Initialize each struct with the same data
+	// Initialize each struct with data from the population profile
+	gen := fixtures.New(fixtures.Small(profile).Optimized)
 	for i := 0; i < numObjects; i++ {
-		structs[i] = model.OptimizedStruct{
-			Int64Field:  123456789,
-			Int64FieldB: 987654321,
-			Int32Field:  123456,
-			Int32FieldB: 654321,
-			Int16Field:  1234,
-			Int16FieldB: 4321,
-			Int8Field:   123,
-			BoolField:   true,
-		}
+		structs[i] = gen(i)
 	}
 
 	endMem := memory.Usage()
-	memUsed := endMem - startMem
+	memUsed := memory.Growth(startMem, endMem)
 	fmt.Printf("Memory used: %d bytes (%s)\n", memUsed, memory.FormatBytes(memUsed))
 	fmt.Printf("Memory per struct: %.2f bytes\n", float64(memUsed)/float64(numObjects))
 
 	// Prevent optimizer from removing our structs before measurements
 	fmt.Printf("Sample value: %v\n", structs[0].Int64Field)
 
-	return memUsed
+	// Measure what a single object retains, including referenced data
+	retained := memory.DeepSize(structs[0])
+	fmt.Printf("Sample shallow size: %d bytes, retained size: %d bytes\n", retained.Shallow, retained.Retained)
+
+	// Record the values the population holds when it is observed
+	observePopulation(observer, structs)
+
+	return memUsed, retained
 }
 
-func testSmallUnoptimizedStructs() uint64 {
+func testSmallUnoptimizedStructs(profile populate.Profile) (uint64, memory.RetainedSize) {
 	startMem := memory.Usage()
 
 	// Create a slice to hold all the structs
 	structs := make([]model.UnoptimizedStruct, numObjects)
 
-	// Initialize each struct with the same data
+	// Initialize each struct with the same data as the optimized version
+	gen := fixtures.New(fixtures.Small(profile).Unoptimized)
 	for i := 0; i < numObjects; i++ {
-		structs[i] = model.UnoptimizedStruct{
-			BoolField:   true,
-			Int8Field:   123,
-			Int16Field:  1234,
-			Int32Field:  123456,
-			BoolFieldB:  false,
-			Int64Field:  123456789,
-			Int16FieldB: 4321,
-			Int32FieldB: 654321,
-			Int64FieldB: 987654321,
-		}
+		structs[i] = gen(i)
 	}
 
 	endMem := memory.Usage()
-	memUsed := endMem - startMem
+	memUsed := memory.Growth(startMem, endMem)
 	fmt.Printf("Memory used: %d bytes (%s)\n", memUsed, memory.FormatBytes(memUsed))
 	fmt.Printf("Memory per struct: %.2f bytes\n", float64(memUsed)/float64(numObjects))
 
 	// Prevent optimizer from removing our structs before measurements
 	fmt.Printf("Sample value: %v\n", structs[0].Int64Field)
 
-	return memUsed
+	// Measure what a single object retains, including referenced data
+	retained := memory.DeepSize(structs[0])
+	fmt.Printf("Sample shallow size: %d bytes, retained size: %d bytes\n", retained.Shallow, retained.Retained)
+
+	return memUsed, retained
 }
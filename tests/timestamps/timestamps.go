@@ -0,0 +1,305 @@
+package timestamps
+
+import (
+	"fmt"
+	model "mem-tests/model/struct"
+	"mem-tests/pkg/layout"
+	"mem-tests/pkg/memory"
+	"mem-tests/pkg/populate"
+	"mem-tests/tests/fixtures"
+	"reflect"
+	"runtime"
+	"time"
+)
+
+const (
+	// timestampObjects is the number of records in every population
+	timestampObjects = 100000
+
+	// conversionPasses is the number of times each conversion loop is repeated when timing it
+	conversionPasses = 20
+)
+
+// conversionSink keeps the results of timestamp reads alive so the compiler cannot drop them
+var conversionSink int64
+
+// stampStats holds the measurements for one timestamp representation of a model
+type stampStats struct {
+	// Size is the size of one struct
+	Size uintptr
+
+	// Layout is the layout of the struct
+	Layout layout.Layout
+
+	// PointerWords is the number of words per struct the collector treats as pointers
+	PointerWords int
+
+	// PointerBytes is the prefix of each struct the collector scans
+	PointerBytes uintptr
+
+	// Retained is the memory held by the population after a collection
+	Retained uint64
+
+	// ScanBytes is the heap the collector scans for pointers because of the population
+	ScanBytes uint64
+
+	// EncodeNs is the time per record, in nanoseconds, to store every timestamp from a time.Time
+	EncodeNs float64
+
+	// DecodeNs is the time per record, in nanoseconds, to read every timestamp back as a time.Time
+	DecodeNs float64
+}
+
+// TimestampTest compares models holding time.Time values against variants
+// storing int64 nanoseconds or int32 seconds since an epoch
+type TimestampTest struct{}
+
+// Name returns the name of this test
+func (t *TimestampTest) Name() string {
+	return "Timestamp Representation Test"
+}
+
+// stampAccess writes and reads every timestamp of a struct through time.Time
+type stampAccess[T any] struct {
+	write func(*T, time.Time)
+	read  func(*T) int64
+}
+
+// timestampCase measures one model in its three timestamp representations
+type timestampCase struct {
+	name         string
+	timeTime     func(count int) stampStats
+	unixNanos    func(count int) stampStats
+	epochSeconds func(count int) stampStats
+}
+
+// newTimestampCase builds a timestampCase from how each representation is built and accessed
+func newTimestampCase[A, B, C any](name string,
+	timeTime func(*fixtures.Values) A, timeAccess stampAccess[A],
+	unixNanos func(*fixtures.Values) B, nanosAccess stampAccess[B],
+	epochSeconds func(*fixtures.Values) C, secondsAccess stampAccess[C],
+) timestampCase {
+	return timestampCase{
+		name: name,
+		timeTime: func(count int) stampStats {
+			return measureStamps(count, fixtures.New(timeTime), timeAccess)
+		},
+		unixNanos: func(count int) stampStats {
+			return measureStamps(count, fixtures.New(unixNanos), nanosAccess)
+		},
+		epochSeconds: func(count int) stampStats {
+			return measureStamps(count, fixtures.New(epochSeconds), secondsAccess)
+		},
+	}
+}
+
+// Run executes the test and returns results
+func (t *TimestampTest) Run() memory.TestResult {
+	result := memory.TestResult{
+		Name:       t.Name(),
+		OtherStats: make(map[string]any),
+	}
+
+	empty := populate.Profile{}
+	testCases := []timestampCase{
+		newTimestampCase("Small Struct",
+			fixtures.Small(empty).Optimized,
+			stampAccess[model.OptimizedStruct]{
+				write: func(v *model.OptimizedStruct, ts time.Time) { v.TimeField = ts },
+				read:  func(v *model.OptimizedStruct) int64 { return v.TimeField.Unix() },
+			},
+			func(v *fixtures.Values) model.SmallUnixNanosStruct { return v.Small(empty).UnixNanos() },
+			stampAccess[model.SmallUnixNanosStruct]{
+				write: func(v *model.SmallUnixNanosStruct, ts time.Time) { v.TimeField = model.NewUnixNanos(ts) },
+				read:  func(v *model.SmallUnixNanosStruct) int64 { return v.TimeField.Time().Unix() },
+			},
+			func(v *fixtures.Values) model.SmallEpochSecondsStruct { return v.Small(empty).EpochSeconds() },
+			stampAccess[model.SmallEpochSecondsStruct]{
+				write: func(v *model.SmallEpochSecondsStruct, ts time.Time) { v.TimeField = model.NewEpochSeconds(ts) },
+				read:  func(v *model.SmallEpochSecondsStruct) int64 { return v.TimeField.Time().Unix() },
+			}),
+		newTimestampCase("Large Struct",
+			fixtures.Large(empty).Optimized,
+			stampAccess[model.LargeOptimizedStruct]{
+				write: func(v *model.LargeOptimizedStruct, ts time.Time) {
+					v.CreatedAt, v.UpdatedAt, v.ExpiresAt, v.DueDate = ts, ts, ts, ts
+				},
+				read: func(v *model.LargeOptimizedStruct) int64 {
+					return v.CreatedAt.Unix() + v.UpdatedAt.Unix() + v.ExpiresAt.Unix() + v.DueDate.Unix()
+				},
+			},
+			func(v *fixtures.Values) model.LargeUnixNanosStruct { return v.Large(empty).UnixNanos() },
+			stampAccess[model.LargeUnixNanosStruct]{
+				write: func(v *model.LargeUnixNanosStruct, ts time.Time) {
+					v.CreatedAt = model.NewUnixNanos(ts)
+					v.UpdatedAt = model.NewUnixNanos(ts)
+					v.ExpiresAt = model.NewUnixNanos(ts)
+					v.DueDate = model.NewUnixNanos(ts)
+				},
+				read: func(v *model.LargeUnixNanosStruct) int64 {
+					return v.CreatedAt.Time().Unix() + v.UpdatedAt.Time().Unix() +
+						v.ExpiresAt.Time().Unix() + v.DueDate.Time().Unix()
+				},
+			},
+			func(v *fixtures.Values) model.LargeEpochSecondsStruct { return v.Large(empty).EpochSeconds() },
+			stampAccess[model.LargeEpochSecondsStruct]{
+				write: func(v *model.LargeEpochSecondsStruct, ts time.Time) {
+					v.CreatedAt = model.NewEpochSeconds(ts)
+					v.UpdatedAt = model.NewEpochSeconds(ts)
+					v.ExpiresAt = model.NewEpochSeconds(ts)
+					v.DueDate = model.NewEpochSeconds(ts)
+				},
+				read: func(v *model.LargeEpochSecondsStruct) int64 {
+					return v.CreatedAt.Time().Unix() + v.UpdatedAt.Time().Unix() +
+						v.ExpiresAt.Time().Unix() + v.DueDate.Time().Unix()
+				},
+			}),
+		newTimestampCase("Database Entity",
+			fixtures.DBEntity.Optimized,
+			stampAccess[model.DBEntityOptimizedStruct]{
+				write: func(v *model.DBEntityOptimizedStruct, ts time.Time) {
+					v.CreatedAt, v.UpdatedAt, v.LastLoginAt = ts, ts, ts
+				},
+				read: func(v *model.DBEntityOptimizedStruct) int64 {
+					return v.CreatedAt.Unix() + v.UpdatedAt.Unix() + v.LastLoginAt.Unix()
+				},
+			},
+			func(v *fixtures.Values) model.DBEntityUnixNanosStruct { return v.DBEntity().UnixNanos() },
+			stampAccess[model.DBEntityUnixNanosStruct]{
+				write: func(v *model.DBEntityUnixNanosStruct, ts time.Time) {
+					v.CreatedAt = model.NewUnixNanos(ts)
+					v.UpdatedAt = model.NewUnixNanos(ts)
+					v.LastLoginAt = model.NewUnixNanos(ts)
+				},
+				read: func(v *model.DBEntityUnixNanosStruct) int64 {
+					return v.CreatedAt.Time().Unix() + v.UpdatedAt.Time().Unix() + v.LastLoginAt.Time().Unix()
+				},
+			},
+			func(v *fixtures.Values) model.DBEntityEpochSecondsStruct { return v.DBEntity().EpochSeconds() },
+			stampAccess[model.DBEntityEpochSecondsStruct]{
+				write: func(v *model.DBEntityEpochSecondsStruct, ts time.Time) {
+					v.CreatedAt = model.NewEpochSeconds(ts)
+					v.UpdatedAt = model.NewEpochSeconds(ts)
+					v.LastLoginAt = model.NewEpochSeconds(ts)
+				},
+				read: func(v *model.DBEntityEpochSecondsStruct) int64 {
+					return v.CreatedAt.Time().Unix() + v.UpdatedAt.Time().Unix() + v.LastLoginAt.Time().Unix()
+				},
+			}),
+	}
+
+	typeResults := make(map[string]map[string]interface{})
+	var totalSaving uint64
+
+	for _, tc := range testCases {
+		fmt.Printf("\n=== Testing %s Timestamps ===\n", tc.name)
+
+		timeTime := tc.timeTime(timestampObjects)
+		unixNanos := tc.unixNanos(timestampObjects)
+		epochSeconds := tc.epochSeconds(timestampObjects)
+
+		printStampStats("time.Time", timeTime)
+		printStampStats("int64 nanoseconds", unixNanos)
+		printStampStats("int32 seconds offset", epochSeconds)
+
+		for _, variant := range []struct {
+			label string
+			stats stampStats
+		}{
+			{"int64 ns", unixNanos},
+			{"int32 offset", epochSeconds},
+		} {
+			typeResult := memory.NewTypeResult(timestampObjects, variant.stats.Retained, timeTime.Retained)
+			typeResult["OptimizedStructSize"] = variant.stats.Size
+			typeResult["UnoptimizedStructSize"] = timeTime.Size
+			typeResult["OptimizedLayout"] = variant.stats.Layout
+			typeResult["UnoptimizedLayout"] = timeTime.Layout
+			typeResult["OptimizedPointerWords"] = variant.stats.PointerWords
+			typeResult["UnoptimizedPointerWords"] = timeTime.PointerWords
+			typeResult["OptimizedPointerBytes"] = variant.stats.PointerBytes
+			typeResult["UnoptimizedPointerBytes"] = timeTime.PointerBytes
+			typeResult["OptimizedScanBytes"] = variant.stats.ScanBytes
+			typeResult["UnoptimizedScanBytes"] = timeTime.ScanBytes
+			typeResult["OptimizedEncodeNs"] = variant.stats.EncodeNs
+			typeResult["UnoptimizedEncodeNs"] = timeTime.EncodeNs
+			typeResult["OptimizedDecodeNs"] = variant.stats.DecodeNs
+			typeResult["UnoptimizedDecodeNs"] = timeTime.DecodeNs
+
+			name := fmt.Sprintf("%s (%s)", tc.name, variant.label)
+			typeResults[name] = typeResult
+			totalSaving += typeResult["MemorySaved"].(uint64)
+		}
+	}
+
+	fmt.Println("\nNote: int64 nanoseconds cover the years 1678 to 2262; int32 seconds drop")
+	fmt.Printf("sub-second precision and cover about 68 years either side of %s.\n",
+		model.TimestampEpoch.Format(time.DateOnly))
+	fmt.Println("Neither keeps the time zone or the monotonic clock reading of a time.Time.")
+
+	result.MemoryUsed = totalSaving
+	result.OtherStats["TypeResults"] = typeResults
+	result.OtherStats["TotalSaving"] = totalSaving
+	result.OtherStats["TimestampEpoch"] = model.TimestampEpoch.Format(time.RFC3339)
+	result.OtherStats["Variants"] = memory.Variants{Optimized: "Compact", Unoptimized: "time.Time"}
+
+	return result
+}
+
+// measureStamps builds a population and times storing every timestamp of
+// every record from a time.Time and reading it back as one
+func measureStamps[T any](n int, gen fixtures.Generator[T], stamps stampAccess[T]) stampStats {
+	l := layout.Of(reflect.TypeFor[T]())
+
+	runtime.GC()
+	startMem := memory.Usage()
+	startScan := memory.HeapScanBytes()
+
+	records := make([]T, n)
+	for i := range records {
+		records[i] = gen(i)
+	}
+
+	runtime.GC()
+	stats := stampStats{
+		Size:         l.Size,
+		Layout:       l,
+		PointerWords: l.PointerWords(),
+		PointerBytes: l.PointerBytes(),
+		Retained:     memory.Growth(startMem, memory.Usage()),
+		ScanBytes:    memory.Growth(startScan, memory.HeapScanBytes()),
+	}
+
+	now := memory.ReferenceTime()
+	start := time.Now()
+	for pass := 0; pass < conversionPasses; pass++ {
+		for i := range records {
+			stamps.write(&records[i], now.Add(time.Duration(i)*time.Second))
+		}
+	}
+	stats.EncodeNs = perRecord(time.Since(start), n)
+
+	start = time.Now()
+	for pass := 0; pass < conversionPasses; pass++ {
+		for i := range records {
+			conversionSink += stamps.read(&records[i])
+		}
+	}
+	stats.DecodeNs = perRecord(time.Since(start), n)
+	runtime.KeepAlive(records)
+
+	return stats
+}
+
+// perRecord returns the nanoseconds per record of a timed conversion loop
+func perRecord(elapsed time.Duration, n int) float64 {
+	return float64(elapsed.Nanoseconds()) / float64(conversionPasses*n)
+}
+
+// printStampStats prints the measurements for one representation
+func printStampStats(label string, stats stampStats) {
+	fmt.Printf("%s:\n", label)
+	fmt.Printf("  Struct size: %d bytes, retained memory: %s\n", stats.Size, memory.FormatBytes(stats.Retained))
+	fmt.Printf("  Pointer words: %d, scanned prefix: %d bytes, GC scan: %s\n",
+		stats.PointerWords, stats.PointerBytes, memory.FormatBytes(stats.ScanBytes))
+	fmt.Printf("  Encode: %.2f ns/record, decode: %.2f ns/record\n", stats.EncodeNs, stats.DecodeNs)
+}